@@ -0,0 +1,413 @@
+package gologster
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// AdminHandler : HTTP обработчик для просмотра и изменения маршрутизации во время работы.
+//                Предназначен для внутреннего отладочного порта:
+//
+//                GET              - таблица маршрутов, накопители, шаблоны, уровни и очереди файлов;
+//                PUT              - изменение уровня или отключение маршрута пакета:
+//                                   {"rule": "github.com/user/app/usecase/...", "level": "debug", "ttl": "10m"}
+//                                   {"rule": "github.com/user/app/usecase/...", "mute": true, "ttl": "5m"}
+//                                   По истечении 'ttl' изменение отменяется. Без 'ttl' оно действует до DELETE;
+//                DELETE ?rule=... - отмена изменения.
+//
+//                HTTP handler for viewing and changing routing at runtime.
+//                Intended for an internal debug port:
+//
+//                GET              - routing table, outputs, templates, levels and file queues;
+//                PUT              - changing the level or muting a package route:
+//                                   {"rule": "github.com/user/app/usecase/...", "level": "debug", "ttl": "10m"}
+//                                   {"rule": "github.com/user/app/usecase/...", "mute": true, "ttl": "5m"}
+//                                   After 'ttl' the change is reverted. Without 'ttl' it lasts until DELETE;
+//                DELETE ?rule=... - reverting the change.
+//
+// EXAMPLE: http.Handle("/debug/logger", logger.AdminHandler())
+//
+func (logger *Logger) AdminHandler() http.Handler {
+	return http.HandlerFunc(logger.admin)
+}
+
+// ruleOverride : изменение маршрута через 'AdminHandler' и уровни, которые восстанавливаются при его отмене.
+//                change of the route via 'AdminHandler' and the levels that are restored when it's reverted.
+//
+type ruleOverride struct {
+	level   *Level
+	muted   bool
+	expires time.Time
+	timer   *time.Timer
+
+	// Прежние уровни маршрута и файлов пакета.
+	// Previous levels of the route and package files.
+	routes []Level
+	files  map[*fileAgent]Level
+}
+
+type adminState struct {
+	Level    Level         `json:"level"`
+	Unrouted int64         `json:"unrouted"`
+	Routes   []adminRoute  `json:"routes"`
+	Console  *adminConsole `json:"console,omitempty"`
+	Files    []adminFile   `json:"files"`
+	Sinks    []adminSink   `json:"sinks"`
+}
+
+type adminRoute struct {
+	Rule     string         `json:"rule"`
+	Kind     string         `json:"kind"`
+	Outputs  []adminOutput  `json:"outputs"`
+	Override *adminOverride `json:"override,omitempty"`
+}
+
+type adminOutput struct {
+	Output      string `json:"output"`
+	Concurrency string `json:"concurrency"`
+	Level       Level  `json:"level"`
+}
+
+type adminOverride struct {
+	Level   *Level     `json:"level,omitempty"`
+	Muted   bool       `json:"muted"`
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+type adminConsole struct {
+	Template    string `json:"template"`
+	Encoder     string `json:"encoder,omitempty"`
+	Level       Level  `json:"level"`
+	StderrLevel Level  `json:"stderr_level"`
+}
+
+type adminFile struct {
+	Key      string      `json:"key"`
+	Path     string      `json:"path"`
+	Mode     string      `json:"mode"`
+	Template string      `json:"template"`
+	Encoder  string      `json:"encoder,omitempty"`
+	Level    Level       `json:"level"`
+	Queue    *QueueStats `json:"queue,omitempty"`
+}
+
+type adminSink struct {
+	Name     string `json:"name"`
+	Template string `json:"template"`
+	Encoder  string `json:"encoder,omitempty"`
+	Level    Level  `json:"level"`
+}
+
+// adminChange : тело запроса PUT. | PUT request body.
+//
+type adminChange struct {
+	Rule  string `json:"rule"`
+	Level *Level `json:"level"`
+	Mute  bool   `json:"mute"`
+	TTL   string `json:"ttl"`
+}
+
+// adminError : ошибка запроса и её HTTP статус. | request error and its HTTP status.
+//
+type adminError struct {
+	status int
+	err    error
+}
+
+func (logger *Logger) admin(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		logger.mx.RLock()
+		state := logger.adminState()
+		logger.mx.RUnlock()
+		adminReply(w, http.StatusOK, state)
+	case http.MethodPut:
+		var change adminChange
+		if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+			adminReply(w, http.StatusBadRequest, adminFail(err))
+			return
+		}
+		route, failure := logger.adminOverride(change)
+		if failure != nil {
+			adminReply(w, failure.status, adminFail(failure.err))
+			return
+		}
+		adminReply(w, http.StatusOK, route)
+	case http.MethodDelete:
+		route, failure := logger.adminRevert(r.URL.Query().Get("rule"))
+		if failure != nil {
+			adminReply(w, failure.status, adminFail(failure.err))
+			return
+		}
+		adminReply(w, http.StatusOK, route)
+	default:
+		w.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodPut, http.MethodDelete}, ", "))
+		adminReply(w, http.StatusMethodNotAllowed, adminFail(errors.New("method isn't allowed : "+r.Method)))
+	}
+}
+
+// adminOverride : применяет изменение маршрута из запроса PUT.
+//                 applies the route change from the PUT request.
+//
+func (logger *Logger) adminOverride(change adminChange) (*adminRoute, *adminError) {
+	if change.Level == nil && !change.Mute {
+		return nil, &adminError{http.StatusBadRequest, errors.New("'level' or 'mute' isn't exist")}
+	}
+	var ttl time.Duration
+	if change.TTL != "" {
+		parsed, err := time.ParseDuration(change.TTL)
+		if err != nil || parsed <= 0 {
+			return nil, &adminError{http.StatusBadRequest, errors.New("invalid ttl '" + change.TTL + "'")}
+		}
+		ttl = parsed
+	}
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	if err := logger.live("Logger.AdminHandler"); err != nil {
+		return nil, &adminError{http.StatusServiceUnavailable, err}
+	}
+	matched, exist := logger.pckgs.keys[change.Rule]
+	if !exist {
+		return nil, &adminError{http.StatusNotFound, errors.New("route isn't exist by rule : '" + change.Rule + "'")}
+	}
+	logger.override(matched, change.Level, change.Mute, ttl)
+	route := logger.adminRoute(matched)
+	return &route, nil
+}
+
+// override : изменяет уровень маршрута 'matched' и файлов его пакета или отключает его.
+//            При 'ttl' > 0 изменение отменяется по таймеру. Вызывается под 'mx.Lock()'.
+//
+//            changes the level of the route 'matched' and its package files or mutes it.
+//            If 'ttl' > 0 the change is reverted by a timer. It's called under 'mx.Lock()'.
+//
+func (logger *Logger) override(matched *rule, level *Level, muted bool, ttl time.Duration) {
+	logger.revert(matched)
+	override := &ruleOverride{
+		level: level,
+		muted: muted,
+		files: make(map[*fileAgent]Level),
+	}
+	for i := range matched.routes {
+		override.routes = append(override.routes, matched.routes[i].level)
+		if level != nil {
+			matched.routes[i].level = *level
+		}
+	}
+	if level != nil {
+		logger.eachFile(func(key string, file *fileAgent) {
+			if key == matched.key {
+				override.files[file] = file.level
+				file.level = *level
+			}
+		})
+		logger.threshold(*level)
+	}
+	if ttl > 0 {
+		override.expires = time.Now().Add(ttl)
+		override.timer = time.AfterFunc(ttl, func() {
+			logger.mx.Lock()
+			defer logger.mx.Unlock()
+			if matched.override == override {
+				logger.revert(matched)
+			}
+		})
+	}
+	matched.override = override
+}
+
+// carryOverrides : переносит изменения 'AdminHandler' из маршрутов 'previous' в одноимённые
+//                  маршруты текущей конфигурации с оставшимся 'ttl'. Таймеры прежних изменений
+//                  останавливаются. Вызывается под 'mx.Lock()' после 'ApplyConfig'.
+//
+//                  carries the 'AdminHandler' changes from the routes of 'previous' over to the routes
+//                  of the current configuration with the same rule and the remaining 'ttl'. Timers of the
+//                  previous changes are stopped. It's called under 'mx.Lock()' after 'ApplyConfig'.
+//
+func (logger *Logger) carryOverrides(previous *router) {
+	for _, old := range previous.rules {
+		override := old.override
+		if override == nil {
+			continue
+		}
+		if override.timer != nil {
+			override.timer.Stop()
+		}
+		old.override = nil
+		var ttl time.Duration
+		if !override.expires.IsZero() {
+			ttl = time.Until(override.expires)
+			if ttl <= 0 {
+				continue
+			}
+		}
+		if matched, exist := logger.pckgs.keys[old.key]; exist {
+			logger.override(matched, override.level, override.muted, ttl)
+		}
+	}
+}
+
+// adminRevert : отменяет изменение маршрута из запроса DELETE.
+//               reverts the route change from the DELETE request.
+//
+func (logger *Logger) adminRevert(key string) (*adminRoute, *adminError) {
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	matched, exist := logger.pckgs.keys[key]
+	if !exist {
+		return nil, &adminError{http.StatusNotFound, errors.New("route isn't exist by rule : '" + key + "'")}
+	}
+	if matched.override == nil {
+		return nil, &adminError{http.StatusNotFound, errors.New("route isn't changed by rule : '" + key + "'")}
+	}
+	logger.revert(matched)
+	route := logger.adminRoute(matched)
+	return &route, nil
+}
+
+// revert : восстанавливает уровни маршрута и файлов пакета. Вызывается под 'mx.Lock()'.
+//          restores the levels of the route and package files. It's called under 'mx.Lock()'.
+//
+func (logger *Logger) revert(matched *rule) {
+	override := matched.override
+	if override == nil {
+		return
+	}
+	if override.timer != nil {
+		override.timer.Stop()
+	}
+	for i := range matched.routes {
+		if i < len(override.routes) {
+			matched.routes[i].level = override.routes[i]
+		}
+	}
+	for file, lvl := range override.files {
+		file.level = lvl
+	}
+	matched.override = nil
+	logger.recalculate()
+}
+
+// adminState : текущее состояние логгера. Вызывается под 'mx.RLock()'.
+//              current state of the logger. It's called under 'mx.RLock()'.
+//
+func (logger *Logger) adminState() adminState {
+	state := adminState{
+		Level:    logger.level,
+		Unrouted: logger.Unrouted(),
+		Routes:   []adminRoute{},
+		Files:    []adminFile{},
+		Sinks:    []adminSink{},
+	}
+	for _, matched := range logger.pckgs.rules {
+		state.Routes = append(state.Routes, logger.adminRoute(matched))
+	}
+	if logger.modeConsole != nil {
+		state.Console = &adminConsole{
+			Template:    adminTemplate(logger.baseConsole.format.tmpl),
+			Encoder:     encoderName(logger.baseConsole.format.encoder),
+			Level:       logger.modeConsole.level,
+			StderrLevel: logger.baseConsole.format.stderrLevel,
+		}
+	}
+	if logger.modeFileMutex != nil {
+		for key, file := range logger.modeFileMutex.config {
+			state.Files = append(state.Files, adminFile{
+				Key:      key,
+				Path:     file.path,
+				Mode:     "file_mutex",
+				Template: adminTemplate(file.format.tmpl),
+				Encoder:  encoderName(file.format.encoder),
+				Level:    file.level,
+			})
+		}
+	}
+	if logger.modeFileMulti != nil {
+		stats := logger.modeFileMulti.stats()
+		for key, file := range logger.modeFileMulti.config {
+			queue := stats[key]
+			state.Files = append(state.Files, adminFile{
+				Key:      key,
+				Path:     file.path,
+				Mode:     "file_multi",
+				Template: adminTemplate(file.format.tmpl),
+				Encoder:  encoderName(file.format.encoder),
+				Level:    file.level,
+				Queue:    &queue,
+			})
+		}
+	}
+	sort.SliceStable(state.Files, func(i, j int) bool {
+		return state.Files[i].Key < state.Files[j].Key
+	})
+	for name, sink := range logger.sinks {
+		state.Sinks = append(state.Sinks, adminSink{
+			Name:     name,
+			Template: adminTemplate(sink.format.tmpl),
+			Encoder:  encoderName(sink.format.encoder),
+			Level:    sink.level,
+		})
+	}
+	sort.Slice(state.Sinks, func(i, j int) bool {
+		return state.Sinks[i].Name < state.Sinks[j].Name
+	})
+	return state
+}
+
+// adminRoute : описание маршрута. | route description.
+//
+func (logger *Logger) adminRoute(matched *rule) adminRoute {
+	route := adminRoute{
+		Rule:    matched.key,
+		Kind:    matched.kindName(),
+		Outputs: []adminOutput{},
+	}
+	for _, output := range matched.routes {
+		isConcurrency := "SingleThreading"
+		if output.isConcurrency {
+			isConcurrency = "MultiThreading"
+		}
+		route.Outputs = append(route.Outputs, adminOutput{
+			Output:      output.output,
+			Concurrency: isConcurrency,
+			Level:       output.level,
+		})
+	}
+	if override := matched.override; override != nil {
+		route.Override = &adminOverride{
+			Level: override.level,
+			Muted: override.muted,
+		}
+		if !override.expires.IsZero() {
+			expires := override.expires
+			route.Override.Expires = &expires
+		}
+	}
+	return route
+}
+
+// adminTemplate : исходный текст шаблона. | template source text.
+//
+func adminTemplate(tmpl *template.Template) string {
+	if tmpl == nil || tmpl.Tree == nil || tmpl.Tree.Root == nil {
+		return ""
+	}
+	return tmpl.Tree.Root.String()
+}
+
+func adminFail(err error) map[string]string {
+	return map[string]string{"error": err.Error()}
+}
+
+func adminReply(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(body)
+}
@@ -0,0 +1,70 @@
+package gologster
+
+import (
+	"strconv"
+	"time"
+)
+
+// Форматы даты, которые выводят время Unix вместо строки по шаблону 'time.Format'.
+// Используются в '.TimeFormat(...)' и функции шаблонов 'time'.
+//
+// Date formats that output Unix time instead of a string by the 'time.Format' layout.
+// They are used in '.TimeFormat(...)' and the 'time' template function.
+const (
+	TimeUnix      = "unix"
+	TimeUnixMilli = "unixmilli"
+	TimeUnixNano  = "unixnano"
+)
+
+// timeLayouts : названия форматов из пакета 'time' для файла конфигурации и шаблонов.
+//               names of layouts from the 'time' package for the configuration file and templates.
+//
+var timeLayouts = map[string]string{
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"RFC1123":     time.RFC1123,
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"StampMilli":  time.StampMilli,
+	"StampMicro":  time.StampMicro,
+	"StampNano":   time.StampNano,
+}
+
+// clock : формат и часовой пояс даты накопителя, заданные через '.TimeFormat(...)'.
+//         date format and time zone of the output set via '.TimeFormat(...)'.
+//
+type clock struct {
+	layout   string
+	location *time.Location
+}
+
+// in : время в часовом поясе накопителя. | time in the output time zone.
+//
+func (c *clock) in(t time.Time) time.Time {
+	if c.location == nil {
+		return t
+	}
+	return t.In(c.location)
+}
+
+func (c *clock) format(t time.Time) string {
+	return formatTime(c.in(t), c.layout)
+}
+
+// formatTime : форматирует время по шаблону 'time.Format', названию из 'timeLayouts' или как время Unix.
+//              formats the time by the 'time.Format' layout, a name from 'timeLayouts' or as Unix time.
+//
+func formatTime(t time.Time, layout string) string {
+	switch layout {
+	case TimeUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case TimeUnixMilli:
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+	case TimeUnixNano:
+		return strconv.FormatInt(t.UnixNano(), 10)
+	}
+	if named, exist := timeLayouts[layout]; exist {
+		layout = named
+	}
+	return t.Format(layout)
+}
@@ -0,0 +1,691 @@
+package gologster
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Конфигурация логгера в формате JSON. | JSON logger configuration.
+//
+// Документ описывает те же инсталлеры, что и Go API:
+// 'default' - инсталлеры режима 'Default(...)', 'packages' - карта 'Packages(...)'.
+//
+// The document describes the same installers as the Go API:
+// 'default' - installers of the 'Default(...)' mode, 'packages' - the 'Packages(...)' map.
+//
+//   {
+//     "default": [
+//       { "type": "console", "template": "{{.Level}} {{.Value}}", "level": "info" },
+//       { "type": "file_multi", "files": { "sql": "logs/sql.txt" },
+//         "rotation": { "max_size": 10485760, "interval": "24h", "max_segments": 7 },
+//         "queue": { "capacity": 5000, "overflow": "drop_oldest" } }
+//     ],
+//     "packages": {
+//       "github.com/user/app/usecase/...": [
+//         { "type": "console", "concurrency": "MultiThreading", "level": "warn" },
+//         { "type": "file_mutex", "file": "logs/usecase.txt",
+//           "permissions": { "file": "0640", "dir": "0750" } }
+//       ]
+//     },
+//     "stack": { "level": "error", "depth": 32, "runtime": false }
+//   }
+//
+// Типы: "console", "file_mutex", "file_multi". Если 'template' не указан, используется 'BaseLogTemplate',
+// вместо шаблона можно указать кодировщик 'encoder': "json", "logfmt" или "pretty".
+// 'template_files' - файлы шаблонов, которые 'template' включает через {{template "name.tmpl" .}} ('.TemplateFiles').
+// 'time_layout' ("RFC3339Nano", "unixmilli", "2006-01-02 15:04:05") и 'time_location' ("UTC", "Europe/Moscow") - '.TimeFormat'.
+// 'concurrency' ("SingleThreading", "MultiThreading") и 'file' используются только в 'packages',
+// 'files' - только в 'default'. Поля 'level', 'stderr_level', 'rotation', 'queue', 'permissions' соответствуют
+// методам инсталлеров '.Level', '.Stderr', '.Rotate', '.Queue', '.Permissions'.
+//
+// Types: "console", "file_mutex", "file_multi". If 'template' isn't specified, 'BaseLogTemplate' is used,
+// an encoder 'encoder' can be specified instead of the template: "json", "logfmt" or "pretty".
+// 'template_files' - template files that 'template' includes via {{template "name.tmpl" .}} ('.TemplateFiles').
+// 'time_layout' ("RFC3339Nano", "unixmilli", "2006-01-02 15:04:05") and 'time_location' ("UTC", "Europe/Moscow") - '.TimeFormat'.
+// 'concurrency' ("SingleThreading", "MultiThreading") and 'file' are used only in 'packages',
+// 'files' - only in 'default'. The fields 'level', 'stderr_level', 'rotation', 'queue', 'permissions' correspond
+// to the installer methods '.Level', '.Stderr', '.Rotate', '.Queue', '.Permissions'.
+//
+// 'stack' - настройки 'Logger.CaptureStack'. | 'stack' - the 'Logger.CaptureStack' settings.
+
+// ConfigError : ошибка в документе конфигурации с положением значения.
+//               error in the configuration document with the position of the value.
+//
+type ConfigError struct {
+	// Строка и столбец, начиная с 1.
+	// Line and column, starting from 1.
+	Line, Column int
+	// Путь значения, например 'packages["github.com/user/app/..."][0].level'.
+	// Value path, for example 'packages["github.com/user/app/..."][0].level'.
+	Path string
+	// Файл конфигурации ('FromFile'), пустой для 'FromConfig'.
+	// Configuration file ('FromFile'), empty for 'FromConfig'.
+	File string
+	Err  error
+}
+
+// Error : implement error interface
+//
+func (e *ConfigError) Error() string {
+	position := strconv.Itoa(e.Line) + ":" + strconv.Itoa(e.Column)
+	if e.File != "" {
+		position = e.File + ":" + position
+	}
+	if e.Path == "" {
+		return position + " : " + e.Err.Error()
+	}
+	return position + " : " + e.Path + " : " + e.Err.Error()
+}
+
+// config : документ конфигурации. | configuration document.
+//
+type config struct {
+	Default  []installerConfig            `json:"default"`
+	Packages map[string][]installerConfig `json:"packages"`
+	Stack    *stackConfig                 `json:"stack"`
+}
+
+// installerConfig : описание одного инсталлера. | description of one installer.
+//
+type installerConfig struct {
+	Type          string             `json:"type"`
+	Template      *string            `json:"template"`
+	Encoder       string             `json:"encoder"`
+	TemplateFiles []string           `json:"template_files"`
+	Concurrency   string             `json:"concurrency"`
+	Level         string             `json:"level"`
+	StderrLevel   string             `json:"stderr_level"`
+	TimeLayout    string             `json:"time_layout"`
+	TimeLocation  string             `json:"time_location"`
+	File          string             `json:"file"`
+	Files         map[string]string  `json:"files"`
+	Rotation      *rotationConfig    `json:"rotation"`
+	Queue         *queueConfig       `json:"queue"`
+	Permissions   *permissionsConfig `json:"permissions"`
+}
+
+type rotationConfig struct {
+	MaxSize      int64  `json:"max_size"`
+	Interval     string `json:"interval"`
+	MaxSegments  int    `json:"max_segments"`
+	MaxTotalSize int64  `json:"max_total_size"`
+}
+
+type queueConfig struct {
+	Capacity  int    `json:"capacity"`
+	Overflow  string `json:"overflow"`
+	Timeout   string `json:"timeout"`
+	SpillPath string `json:"spill_path"`
+}
+
+type stackConfig struct {
+	Level   string `json:"level"`
+	Depth   int    `json:"depth"`
+	Runtime bool   `json:"runtime"`
+}
+
+type permissionsConfig struct {
+	File string `json:"file"`
+	Dir  string `json:"dir"`
+}
+
+// configFields : допустимые поля объектов документа. | allowed fields of document objects.
+//
+var configFields = map[string][]string{
+	"":            {"default", "packages", "stack"},
+	"installer":   {"type", "template", "template_files", "encoder", "concurrency", "level", "stderr_level", "time_layout", "time_location", "file", "files", "rotation", "queue", "permissions"},
+	"rotation":    {"max_size", "interval", "max_segments", "max_total_size"},
+	"queue":       {"capacity", "overflow", "timeout", "spill_path"},
+	"permissions": {"file", "dir"},
+	"stack":       {"level", "depth", "runtime"},
+}
+
+// configEncoders : значения 'encoder'. | values of 'encoder'.
+//
+var configEncoders = map[string]func() Encoder{
+	"json":   JSONEncoder,
+	"logfmt": LogfmtEncoder,
+	"pretty": PrettyEncoder,
+}
+
+// configOverflow : значения 'queue.overflow'. | values of 'queue.overflow'.
+//
+var configOverflow = map[string]Overflow{
+	"block":         OverflowBlock,
+	"block_timeout": OverflowBlockTimeout,
+	"drop_newest":   OverflowDropNewest,
+	"drop_oldest":   OverflowDropOldest,
+	"spill":         OverflowSpill,
+}
+
+// FromConfig : создаёт логгер по документу конфигурации в формате JSON.
+//              Ошибка в документе возвращается как '*ConfigError' со строкой и столбцом.
+//
+//              creates a logger from the JSON configuration document.
+//              An error in the document is returned as '*ConfigError' with the line and column.
+//
+func FromConfig(reader io.Reader) (*Logger, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, errors.New("FromConfig : " + err.Error())
+	}
+	loaded, err := parseConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	logger, err := loaded.build(newBase())
+	if err != nil {
+		logger.discard()
+		return nil, err
+	}
+	return logger, nil
+}
+
+// FromFile : создаёт логгер по файлу конфигурации в формате JSON.
+//            Ошибка в документе возвращается как '*ConfigError' с заполненным полем 'File'.
+//
+//            creates a logger from the JSON configuration file.
+//            An error in the document is returned as '*ConfigError' with the 'File' field filled.
+//
+func FromFile(path string) (*Logger, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.New("FromFile : " + err.Error())
+	}
+	defer file.Close()
+	logger, err := FromConfig(file)
+	if configErr, ok := err.(*ConfigError); ok {
+		configErr.File = path
+	}
+	return logger, err
+}
+
+// loadedConfig : проверенная конфигурация, готовая к установке.
+//                checked configuration ready to be installed.
+//
+type loadedConfig struct {
+	defaults []DefaultInstaller
+	packages map[string][]PackageInstaller
+	stack    *StackCapture
+}
+
+// parseConfig : разбирает и проверяет документ конфигурации.
+//               parses and checks the configuration document.
+//
+func parseConfig(data []byte) (*loadedConfig, error) {
+	var document config
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, configSyntaxError(data, err)
+	}
+	parser := &configParser{index: newConfigIndex(data)}
+	loaded := &loadedConfig{packages: make(map[string][]PackageInstaller)}
+	if err := parser.fields("", ""); err != nil {
+		return nil, err
+	}
+	for i, installer := range document.Default {
+		path := configItem("default", i)
+		mode, err := parser.defaultInstaller(path, installer)
+		if err != nil {
+			return nil, err
+		}
+		loaded.defaults = append(loaded.defaults, mode)
+	}
+	names := make([]string, 0, len(document.Packages))
+	for name := range document.Packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := configField("packages", name)
+		if _, err := newRule(name); err != nil {
+			return nil, parser.fail(path, err)
+		}
+		loaded.packages[name] = []PackageInstaller{}
+		for i, installer := range document.Packages[name] {
+			mode, err := parser.packageInstaller(configItem(path, i), installer)
+			if err != nil {
+				return nil, err
+			}
+			loaded.packages[name] = append(loaded.packages[name], mode)
+		}
+	}
+	if document.Stack != nil {
+		stack, err := parser.stack(document.Stack)
+		if err != nil {
+			return nil, err
+		}
+		loaded.stack = stack
+	}
+	return loaded, nil
+}
+
+// stack : разбирает 'stack' - настройки 'Logger.CaptureStack'.
+//         parses 'stack' - the 'Logger.CaptureStack' settings.
+//
+func (parser *configParser) stack(c *stackConfig) (*StackCapture, error) {
+	if err := parser.fields("stack", "stack"); err != nil {
+		return nil, err
+	}
+	capture := &StackCapture{Depth: c.Depth, Runtime: c.Runtime}
+	if c.Level != "" {
+		if err := capture.Level.UnmarshalText([]byte(c.Level)); err != nil {
+			return nil, parser.fail("stack.level", err)
+		}
+	}
+	if c.Depth < 0 {
+		return nil, parser.fail("stack.depth", errors.New("depth can't be negative"))
+	}
+	return normalizeStack(capture), nil
+}
+
+// build : создаёт логгер так же, как это сделал бы Go API. В отличие от 'Default' / 'Packages',
+//         ошибки инсталлеров (например, отсутствующие 'template_files') возвращаются, а не выводятся
+//         в консоль, чтобы документ был отклонён целиком. Логгер возвращается и при ошибке,
+//         чтобы вызывающий код мог освободить его файлы ('discard').
+//
+//         creates a logger the same way the Go API would. Unlike 'Default' / 'Packages',
+//         installer errors (for example, missing 'template_files') are returned rather than output
+//         to the console, so that the document is rejected as a whole. The logger is returned even on error,
+//         so that the calling code can release its files ('discard').
+//
+func (loaded *loadedConfig) build(base *loggerBase) (*Logger, error) {
+	logger := newLogger(base)
+	logger.stack = loaded.stack
+	var errs []error
+	if len(loaded.packages) == 0 {
+		errs = logger.installDefault(loaded.defaults...)
+	} else {
+		errs = logger.installPackages(loaded.packages)
+		for _, mode := range loaded.defaults {
+			if err := mode(logger); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	logger.applyEnv()
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return logger, joinErrors("FromConfig", messages)
+}
+
+// configSyntaxError : ошибка 'encoding/json' с положением в документе.
+//                     'encoding/json' error with the position in the document.
+//
+func configSyntaxError(data []byte, err error) error {
+	index := &configIndex{data: data}
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		// 'Offset' включает неверный символ, конец документа указывается как есть.
+		// 'Offset' includes the invalid character, the end of the document is reported as is.
+		offset := int(e.Offset)
+		if offset > 0 && e.Error() != "unexpected end of JSON input" {
+			offset--
+		}
+		line, column := index.lineColumn(offset)
+		return &ConfigError{Line: line, Column: column, Err: err}
+	case *json.UnmarshalTypeError:
+		// Синтаксис документа уже проверен, поэтому его можно разобрать на значения.
+		// The document syntax is already checked, so it can be split into values.
+		index = newConfigIndex(data)
+		if path, exist := index.enclosing(int(e.Offset)); exist {
+			line, column := index.position(path)
+			return &ConfigError{Line: line, Column: column, Path: path, Err: errors.New("cannot unmarshal " + e.Value + " into " + e.Type.String())}
+		}
+		line, column := index.lineColumn(int(e.Offset))
+		return &ConfigError{Line: line, Column: column, Path: e.Field, Err: err}
+	default:
+		line, column := index.lineColumn(len(data))
+		return &ConfigError{Line: line, Column: column, Err: err}
+	}
+}
+
+// configParser : проверяет значения документа и создаёт инсталлеры.
+//                checks the document values and creates installers.
+//
+type configParser struct {
+	index *configIndex
+}
+
+// fail : ошибка значения 'path'. | error of the value 'path'.
+//
+func (parser *configParser) fail(path string, err error) error {
+	line, column := parser.index.position(path)
+	return &ConfigError{Line: line, Column: column, Path: path, Err: err}
+}
+
+// fields : проверяет, что объект 'path' содержит только допустимые поля вида 'kind'.
+//          checks that the object 'path' contains only allowed fields of the kind 'kind'.
+//
+func (parser *configParser) fields(path, kind string) error {
+	for _, key := range parser.index.keys[path] {
+		known := false
+		for _, field := range configFields[kind] {
+			if key == field {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return parser.fail(configField(path, key), errors.New("unknown field '"+key+"'"))
+		}
+	}
+	return nil
+}
+
+// defaultInstaller : инсталлер из элемента 'default'. | installer from a 'default' item.
+//
+func (parser *configParser) defaultInstaller(path string, c installerConfig) (DefaultInstaller, error) {
+	if err := parser.common(path, c); err != nil {
+		return nil, err
+	}
+	if c.Concurrency != "" {
+		return nil, parser.fail(path+".concurrency", errors.New("'concurrency' is used only in 'packages'"))
+	}
+	if c.File != "" {
+		return nil, parser.fail(path+".file", errors.New("'file' is used only in 'packages', use 'files'"))
+	}
+	var mode DefaultInstaller
+	tmpl := parser.template(c)
+	switch c.Type {
+	case "console":
+		if len(c.Files) != 0 {
+			return nil, parser.fail(path+".files", errors.New("'files' isn't used by 'console'"))
+		}
+		mode = DefaultConsoleSimple(tmpl)
+	case "file_mutex", "file_multi":
+		if len(c.Files) == 0 {
+			return nil, parser.fail(path, errors.New("'files' isn't exist"))
+		}
+		if c.Type == "file_mutex" {
+			mode = DefaultFileMutex(tmpl, c.Files)
+		} else {
+			mode = DefaultFileMulti(tmpl, c.Files)
+		}
+	}
+	return parser.defaultSettings(path, c, mode)
+}
+
+// packageInstaller : инсталлер из элемента 'packages'. | installer from a 'packages' item.
+//
+func (parser *configParser) packageInstaller(path string, c installerConfig) (PackageInstaller, error) {
+	if err := parser.common(path, c); err != nil {
+		return nil, err
+	}
+	if len(c.Files) != 0 {
+		return nil, parser.fail(path+".files", errors.New("'files' is used only in 'default', use 'file'"))
+	}
+	var isConcurrency concurrency
+	switch strings.ToLower(c.Concurrency) {
+	case "", "singlethreading":
+		isConcurrency = SingleThreading
+	case "multithreading":
+		isConcurrency = MultiThreading
+	default:
+		return nil, parser.fail(path+".concurrency", errors.New("unknown concurrency '"+c.Concurrency+"'"))
+	}
+	var mode PackageInstaller
+	tmpl := parser.template(c)
+	switch c.Type {
+	case "console":
+		if c.File != "" {
+			return nil, parser.fail(path+".file", errors.New("'file' isn't used by 'console'"))
+		}
+		mode = PackageConsoleSimple(tmpl, isConcurrency)
+	case "file_mutex", "file_multi":
+		if c.File == "" {
+			return nil, parser.fail(path, errors.New("'file' isn't exist"))
+		}
+		if c.Type == "file_mutex" {
+			mode = PackageFileMutex(tmpl, isConcurrency, c.File)
+		} else {
+			mode = PackageFileMulti(tmpl, isConcurrency, c.File)
+		}
+	}
+	return parser.packageSettings(path, c, mode)
+}
+
+// common : проверки, общие для 'default' и 'packages'.
+//          checks common to 'default' and 'packages'.
+//
+func (parser *configParser) common(path string, c installerConfig) error {
+	for _, kind := range []string{"installer", "rotation", "queue", "permissions"} {
+		object := path
+		if kind != "installer" {
+			object = path + "." + kind
+		}
+		if err := parser.fields(object, kind); err != nil {
+			return err
+		}
+	}
+	switch c.Type {
+	case "console", "file_mutex", "file_multi":
+	case "":
+		return parser.fail(path, errors.New("'type' isn't exist"))
+	default:
+		return parser.fail(path+".type", errors.New("unknown type '"+c.Type+"'"))
+	}
+	if c.Template != nil {
+		if _, err := template.New("config").Funcs(TemplateFuncs()).Parse(*c.Template); err != nil {
+			return parser.fail(path+".template", err)
+		}
+	}
+	if c.Encoder != "" {
+		if _, exist := configEncoders[strings.ToLower(c.Encoder)]; !exist {
+			return parser.fail(path+".encoder", errors.New("unknown encoder '"+c.Encoder+"'"))
+		}
+		if c.Template != nil || len(c.TemplateFiles) != 0 {
+			return parser.fail(path+".encoder", errors.New("'template' and 'encoder' can't be used together"))
+		}
+	}
+	if c.Type == "console" && (c.Rotation != nil || c.Queue != nil || c.Permissions != nil) {
+		return parser.fail(path, errors.New("'rotation', 'queue' and 'permissions' aren't used by 'console'"))
+	}
+	if c.Type != "console" && c.StderrLevel != "" {
+		return parser.fail(path+".stderr_level", errors.New("'stderr_level' is used only by 'console'"))
+	}
+	if c.Type == "file_mutex" && c.Queue != nil {
+		return parser.fail(path+".queue", errors.New("'queue' is used only by 'file_multi'"))
+	}
+	return nil
+}
+
+func (parser *configParser) template(c installerConfig) string {
+	if c.Template == nil {
+		if len(c.TemplateFiles) != 0 {
+			return ""
+		}
+		return BaseLogTemplate
+	}
+	return *c.Template
+}
+
+// settings : разбирает 'level', 'rotation', 'queue', 'permissions' и возвращает
+//            функции установки, которые применяются к инсталлеру через его методы.
+//
+//            parses 'level', 'rotation', 'queue', 'permissions' and returns
+//            the setting functions that are applied to the installer via its methods.
+//
+func (parser *configParser) settings(path string, c installerConfig) (*configSettings, error) {
+	parsed := new(configSettings)
+	if c.Encoder != "" {
+		parsed.encoder = configEncoders[strings.ToLower(c.Encoder)]()
+	}
+	if c.TimeLayout != "" || c.TimeLocation != "" {
+		parsed.clock = &clock{layout: c.TimeLayout}
+		if c.TimeLayout == "" {
+			parsed.clock.layout = dateLayout
+		}
+		if c.TimeLocation != "" {
+			location, err := time.LoadLocation(c.TimeLocation)
+			if err != nil {
+				return nil, parser.fail(path+".time_location", err)
+			}
+			parsed.clock.location = location
+		}
+	}
+	if c.StderrLevel != "" {
+		var lvl Level
+		if err := lvl.UnmarshalText([]byte(c.StderrLevel)); err != nil {
+			return nil, parser.fail(path+".stderr_level", err)
+		}
+		parsed.stderrLevel = &lvl
+	}
+	if c.Level != "" {
+		var lvl Level
+		if err := lvl.UnmarshalText([]byte(c.Level)); err != nil {
+			return nil, parser.fail(path+".level", err)
+		}
+		parsed.level = &lvl
+	}
+	if c.Rotation != nil {
+		rotation := Rotation{
+			MaxSize:      c.Rotation.MaxSize,
+			MaxSegments:  c.Rotation.MaxSegments,
+			MaxTotalSize: c.Rotation.MaxTotalSize,
+		}
+		if c.Rotation.Interval != "" {
+			interval, err := time.ParseDuration(c.Rotation.Interval)
+			if err != nil {
+				return nil, parser.fail(path+".rotation.interval", err)
+			}
+			rotation.Interval = interval
+		}
+		parsed.rotation = &rotation
+	}
+	if c.Queue != nil {
+		queue := Queue{
+			Capacity:  c.Queue.Capacity,
+			SpillPath: c.Queue.SpillPath,
+		}
+		if c.Queue.Capacity <= 0 {
+			queue.Capacity = defaultQueueCapacity
+		}
+		if c.Queue.Overflow != "" {
+			overflow, exist := configOverflow[strings.ToLower(c.Queue.Overflow)]
+			if !exist {
+				return nil, parser.fail(path+".queue.overflow", errors.New("unknown overflow '"+c.Queue.Overflow+"'"))
+			}
+			queue.Overflow = overflow
+		}
+		if c.Queue.Timeout != "" {
+			timeout, err := time.ParseDuration(c.Queue.Timeout)
+			if err != nil {
+				return nil, parser.fail(path+".queue.timeout", err)
+			}
+			queue.Timeout = timeout
+		}
+		parsed.queue = &queue
+	}
+	if c.Permissions != nil {
+		var err error
+		if parsed.perm, err = parser.permission(path+".permissions.file", c.Permissions.File, 0666); err != nil {
+			return nil, err
+		}
+		if parsed.dirPerm, err = parser.permission(path+".permissions.dir", c.Permissions.Dir, 0755); err != nil {
+			return nil, err
+		}
+		parsed.permissions = true
+	}
+	return parsed, nil
+}
+
+// permission : разбирает права доступа в восьмеричной записи, например "0640".
+//              parses permissions in octal notation, for example "0640".
+//
+func (parser *configParser) permission(path, value string, alternative os.FileMode) (os.FileMode, error) {
+	if value == "" {
+		return alternative, nil
+	}
+	perm, err := strconv.ParseUint(value, 8, 32)
+	if err != nil || perm > 0777 {
+		return 0, parser.fail(path, errors.New("invalid permissions '"+value+"'"))
+	}
+	return os.FileMode(perm), nil
+}
+
+// configSettings : разобранные настройки инсталлера. | parsed installer settings.
+//
+type configSettings struct {
+	encoder       Encoder
+	level         *Level
+	stderrLevel   *Level
+	clock         *clock
+	rotation      *Rotation
+	queue         *Queue
+	perm, dirPerm os.FileMode
+	permissions   bool
+}
+
+func (parser *configParser) defaultSettings(path string, c installerConfig, mode DefaultInstaller) (DefaultInstaller, error) {
+	parsed, err := parser.settings(path, c)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.level != nil {
+		mode = mode.Level(*parsed.level)
+	}
+	if parsed.stderrLevel != nil {
+		mode = mode.Stderr(*parsed.stderrLevel)
+	}
+	if parsed.clock != nil {
+		mode = mode.TimeFormat(parsed.clock.layout, parsed.clock.location)
+	}
+	if parsed.rotation != nil {
+		mode = mode.Rotate(*parsed.rotation)
+	}
+	if parsed.queue != nil {
+		mode = mode.Queue(*parsed.queue)
+	}
+	if parsed.permissions {
+		mode = mode.Permissions(parsed.perm, parsed.dirPerm)
+	}
+	if parsed.encoder != nil {
+		mode = mode.Encoder(parsed.encoder)
+	}
+	if len(c.TemplateFiles) != 0 {
+		mode = mode.TemplateFiles(c.TemplateFiles...)
+	}
+	return mode, nil
+}
+
+func (parser *configParser) packageSettings(path string, c installerConfig, mode PackageInstaller) (PackageInstaller, error) {
+	parsed, err := parser.settings(path, c)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.level != nil {
+		mode = mode.Level(*parsed.level)
+	}
+	if parsed.stderrLevel != nil {
+		mode = mode.Stderr(*parsed.stderrLevel)
+	}
+	if parsed.clock != nil {
+		mode = mode.TimeFormat(parsed.clock.layout, parsed.clock.location)
+	}
+	if parsed.rotation != nil {
+		mode = mode.Rotate(*parsed.rotation)
+	}
+	if parsed.queue != nil {
+		mode = mode.Queue(*parsed.queue)
+	}
+	if parsed.permissions {
+		mode = mode.Permissions(parsed.perm, parsed.dirPerm)
+	}
+	if parsed.encoder != nil {
+		mode = mode.Encoder(parsed.encoder)
+	}
+	if len(c.TemplateFiles) != 0 {
+		mode = mode.TemplateFiles(c.TemplateFiles...)
+	}
+	return mode, nil
+}
@@ -0,0 +1,206 @@
+package gologster
+
+import (
+	"encoding/json"
+	"strconv"
+	"unicode/utf8"
+)
+
+// configIndex : положения значений в JSON документе конфигурации.
+//               Используется только для вывода строки и столбца в ошибках.
+//
+//               positions of values in the JSON configuration document.
+//               It's used only for outputting the line and column in errors.
+//
+// Путь значения записывается так же, как в сообщениях об ошибках:
+// 'packages["github.com/user/app/..."][0].level'.
+//
+// The value path is written the same way as in error messages:
+// 'packages["github.com/user/app/..."][0].level'.
+//
+type configIndex struct {
+	data []byte
+	// path -> смещение начала значения. | path -> offset of the value start.
+	offsets map[string]int
+	// path -> смещение сразу после значения. | path -> offset right after the value.
+	ends map[string]int
+	// path объекта -> ключи в порядке документа. | object path -> keys in document order.
+	keys map[string][]string
+}
+
+// newConfigIndex : constructor
+//
+// Документ должен быть уже проверен 'json.Unmarshal'.
+//
+// The document must already be checked by 'json.Unmarshal'.
+//
+func newConfigIndex(data []byte) *configIndex {
+	index := new(configIndex)
+	index.data = data
+	index.offsets = make(map[string]int)
+	index.ends = make(map[string]int)
+	index.keys = make(map[string][]string)
+	index.value(0, "")
+	return index
+}
+
+// position : строка и столбец (начиная с 1) первого существующего значения из 'paths'.
+//            line and column (starting from 1) of the first existing value from 'paths'.
+//
+func (index *configIndex) position(paths ...string) (int, int) {
+	for _, path := range paths {
+		if offset, exist := index.offsets[path]; exist {
+			return index.lineColumn(offset)
+		}
+	}
+	return 1, 1
+}
+
+// enclosing : путь самого вложенного значения, которое заканчивается не раньше 'offset'
+//             и начинается раньше него ('json.UnmarshalTypeError.Offset' указывает на конец значения).
+//
+//             path of the innermost value that ends not earlier than 'offset'
+//             and starts before it ('json.UnmarshalTypeError.Offset' points to the value end).
+//
+func (index *configIndex) enclosing(offset int) (string, bool) {
+	var (
+		found string
+		start = -1
+	)
+	for path, begin := range index.offsets {
+		if begin < offset && index.ends[path] >= offset && begin > start {
+			found, start = path, begin
+		}
+	}
+	return found, start >= 0
+}
+
+// lineColumn : строка и столбец (начиная с 1) по смещению в документе.
+//              line and column (starting from 1) by the offset in the document.
+//
+func (index *configIndex) lineColumn(offset int) (int, int) {
+	if offset > len(index.data) {
+		offset = len(index.data)
+	}
+	line, start := 1, 0
+	for i := 0; i < offset; i++ {
+		if index.data[i] == '\n' {
+			line++
+			start = i + 1
+		}
+	}
+	return line, utf8.RuneCount(index.data[start:offset]) + 1
+}
+
+// value : запоминает положение значения 'path', начинающегося не раньше 'i',
+//         и возвращает смещение сразу после него.
+//
+//         remembers the position of the value 'path' starting not earlier than 'i',
+//         and returns the offset right after it.
+//
+func (index *configIndex) value(i int, path string) int {
+	i = index.space(i)
+	if i >= len(index.data) {
+		return i
+	}
+	index.offsets[path] = i
+	end := index.scan(i, path)
+	index.ends[path] = end
+	return end
+}
+
+// scan : смещение сразу после значения 'path', начинающегося в 'i'.
+//        offset right after the value 'path' starting at 'i'.
+//
+func (index *configIndex) scan(i int, path string) int {
+	switch index.data[i] {
+	case '{':
+		index.keys[path] = []string{}
+		i = index.space(i + 1)
+		for i < len(index.data) && index.data[i] != '}' {
+			end := index.str(i)
+			var key string
+			_ = json.Unmarshal(index.data[i:end], &key)
+			index.keys[path] = append(index.keys[path], key)
+			i = index.space(end) + 1
+			i = index.space(index.value(i, configField(path, key)))
+			if i < len(index.data) && index.data[i] == ',' {
+				i = index.space(i + 1)
+			}
+		}
+		return i + 1
+	case '[':
+		i = index.space(i + 1)
+		for item := 0; i < len(index.data) && index.data[i] != ']'; item++ {
+			i = index.space(index.value(i, configItem(path, item)))
+			if i < len(index.data) && index.data[i] == ',' {
+				i = index.space(i + 1)
+			}
+		}
+		return i + 1
+	case '"':
+		return index.str(i)
+	default:
+		for i < len(index.data) {
+			switch index.data[i] {
+			case ',', '}', ']', ' ', '\t', '\r', '\n':
+				return i
+			}
+			i++
+		}
+		return i
+	}
+}
+
+// str : смещение сразу после строки, начинающейся в 'i'.
+//       offset right after the string starting at 'i'.
+//
+func (index *configIndex) str(i int) int {
+	for i++; i < len(index.data); i++ {
+		switch index.data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return i
+}
+
+func (index *configIndex) space(i int) int {
+	for i < len(index.data) {
+		switch index.data[i] {
+		case ' ', '\t', '\r', '\n':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// configField : путь поля объекта. | path of an object field.
+//
+func configField(path, key string) string {
+	identifier := key != ""
+	for _, r := range key {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			identifier = false
+			break
+		}
+	}
+	switch {
+	case !identifier:
+		return path + "[" + strconv.Quote(key) + "]"
+	case path == "":
+		return key
+	default:
+		return path + "." + key
+	}
+}
+
+// configItem : путь элемента массива. | path of an array item.
+//
+func configItem(path string, item int) string {
+	return path + "[" + strconv.Itoa(item) + "]"
+}
@@ -0,0 +1,102 @@
+package gologster
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigErrorPosition(t *testing.T) {
+	tests := []struct {
+		doc          string
+		line, column int
+		path         string
+	}{
+		{
+			doc:  "{\"default\": [\n  {\"type\": \"console\", \"level\": \"loud\"}]}",
+			line: 2, column: 32, path: "default[0].level",
+		},
+		{
+			doc:  "{\"packages\": {\n  \"github.com/user/app/...\": [\n    {\"type\": \"file_multi\", \"file\": \"a.txt\",\n     \"queue\": {\"overflow\": \"later\"}}]}}",
+			line: 4, column: 28, path: `packages["github.com/user/app/..."][0].queue.overflow`,
+		},
+		{
+			doc:  "{\"default\": [\n  {\"type\": \"console\",\n   \"colour\": true}]}",
+			line: 3, column: 14, path: "default[0].colour",
+		},
+		{
+			doc:  "{\"default\": [\n  {\"type\": \"console\", \"level\": 5}]}",
+			line: 2, column: 32, path: "default[0].level",
+		},
+		{
+			doc:  "{\"default\": [\n  {\"type\": \"file_mutex\",\n   \"rotation\": {\"max_size\": [1]}}]}",
+			line: 3, column: 29, path: "default[0].rotation.max_size",
+		},
+		{
+			doc:  "{\"default\": [\n  {\"type\": \"console\",, }]}",
+			line: 2, column: 22,
+		},
+		{
+			doc:  "{\"default\": [\n  {\"type\": \"console\"}]",
+			line: 2, column: 23,
+		},
+	}
+	for _, test := range tests {
+		logger, err := FromConfig(strings.NewReader(test.doc))
+		if logger != nil {
+			t.Errorf("%q : got logger with error %v", test.doc, err)
+		}
+		configErr, ok := err.(*ConfigError)
+		if !ok {
+			t.Errorf("%q : got %T %v, want *ConfigError", test.doc, err, err)
+			continue
+		}
+		if configErr.Line != test.line || configErr.Column != test.column || configErr.Path != test.path {
+			t.Errorf("%q : got %d:%d '%s', want %d:%d '%s'", test.doc,
+				configErr.Line, configErr.Column, configErr.Path, test.line, test.column, test.path)
+		}
+	}
+}
+
+func TestFromFileConfigError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gologster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "logster.json")
+	if err := ioutil.WriteFile(path, []byte("{\"default\": [\n  {\"type\": \"console\", \"level\": \"loud\"}]}"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	_, err = FromFile(path)
+	configErr, ok := err.(*ConfigError)
+	if !ok {
+		t.Fatalf("got %T %v, want *ConfigError", err, err)
+	}
+	if configErr.File != path || !strings.HasPrefix(err.Error(), path+":2:32 : ") {
+		t.Errorf("got '%s', want the position prefixed with '%s'", err, path)
+	}
+}
+
+func TestConfigInstallerError(t *testing.T) {
+	const doc = `{"default": [{"type": "console", "template_files": ["/nonexistent/*.tmpl"]}]}`
+	logger, err := FromConfig(strings.NewReader(doc))
+	if err == nil || logger != nil {
+		t.Fatalf("got (%v, %v), want installer error", logger, err)
+	}
+	if _, ok := err.(*ConfigError); ok {
+		t.Errorf("got *ConfigError %v, want installer error", err)
+	}
+	var out bytes.Buffer
+	logger = Default(DefaultConsoleSimple(BaseLogTemplate).Writers(&out, &out))
+	if err := logger.ApplyConfig(strings.NewReader(doc)); err == nil {
+		t.Fatal("ApplyConfig : want installer error")
+	}
+	logger.Info("previous console")
+	if !strings.Contains(out.String(), "previous console") {
+		t.Errorf("previous configuration isn't kept : %q", out.String())
+	}
+}
@@ -0,0 +1,202 @@
+package gologster
+
+import (
+	"context"
+	"errors"
+	"runtime/pprof"
+)
+
+// ContextExtractor : извлекает поля лога из контекста методов '*Ctx' ('InfoCtx', ...).
+//                    Возвращает nil, если в контексте нет нужных значений.
+//
+//                    extracts log fields from the context of the '*Ctx' methods ('InfoCtx', ...).
+//                    Returns nil if the context doesn't contain the required values.
+//
+type ContextExtractor func(ctx context.Context) Fields
+
+// ContextValue : поле 'field' со значением 'ctx.Value(key)', например идентификатор запроса,
+//                пользователя или арендатора.
+//
+//                field 'field' with the value 'ctx.Value(key)', for example the request,
+//                user or tenant ID.
+//
+// EXAMPLE: gologster.ContextValue(requestIDKey{}, "request_id")
+//
+func ContextValue(key interface{}, field string) ContextExtractor {
+	return func(ctx context.Context) Fields {
+		value := ctx.Value(key)
+		if value == nil {
+			return nil
+		}
+		return Fields{field: value}
+	}
+}
+
+// ContextPprofLabels : метки 'pprof.Labels' контекста, каждая метка - отдельное поле.
+//                      'pprof.Labels' labels of the context, each label is a separate field.
+//
+func ContextPprofLabels() ContextExtractor {
+	return func(ctx context.Context) Fields {
+		var fields Fields
+		pprof.ForLabels(ctx, func(key, value string) bool {
+			if fields == nil {
+				fields = make(Fields)
+			}
+			fields[key] = value
+			return true
+		})
+		return fields
+	}
+}
+
+// DefaultContext : регистрирует извлекатели полей из контекста методов '*Ctx'.
+//                  registers extractors of fields from the context of the '*Ctx' methods.
+//
+// EXAMPLE: gologster.Default(gologster.DefaultConsoleSimple(...), gologster.DefaultContext(gologster.ContextPprofLabels()))
+//
+func DefaultContext(extractors ...ContextExtractor) DefaultInstaller {
+	return func(logger *Logger) error {
+		logger.extract(extractors...)
+		return nil
+	}
+}
+
+// PackageContext : регистрирует извлекатели полей из контекста методов '*Ctx' для логов пакетов,
+//                  подошедших под правило 'pckg'. Извлекатели хранятся вместе с правилом, поэтому
+//                  'ReplacePackage' и 'RemovePackage' заменяют и удаляют их вместе с маршрутом.
+//
+//                  registers extractors of fields from the context of the '*Ctx' methods for logs of the packages
+//                  that matched the rule 'pckg'. The extractors are stored together with the rule, so
+//                  'ReplacePackage' and 'RemovePackage' replace and remove them together with the route.
+//
+func PackageContext(extractors ...ContextExtractor) PackageInstaller {
+	return func(logger *Logger, pckg string) error {
+		matched, err := logger.pckgs.rule(pckg)
+		if err != nil {
+			return errors.New("PackageContext : " + err.Error())
+		}
+		matched.extractors = appendExtractors(matched.extractors, extractors...)
+		return nil
+	}
+}
+
+// extract : добавляет извлекатели режима 'Default'. | adds 'Default' mode extractors.
+//
+func (logger *Logger) extract(extractors ...ContextExtractor) {
+	logger.extractors = appendExtractors(logger.extractors, extractors...)
+}
+
+// appendExtractors : добавляет извлекатели, пропуская nil. | appends extractors, skipping nil.
+//
+func appendExtractors(to []ContextExtractor, extractors ...ContextExtractor) []ContextExtractor {
+	for _, extractor := range extractors {
+		if extractor != nil {
+			to = append(to, extractor)
+		}
+	}
+	return to
+}
+
+// carryExtractors : переносит извлекатели 'PackageContext' в правила с тем же ключом ('ApplyConfig').
+//                   carries the 'PackageContext' extractors over to the rules with the same key ('ApplyConfig').
+//
+func (logger *Logger) carryExtractors(previous *router) {
+	for _, old := range previous.rules {
+		if matched, exist := logger.pckgs.keys[old.key]; exist && len(old.extractors) != 0 {
+			matched.extractors = appendExtractors(matched.extractors, old.extractors...)
+		}
+	}
+}
+
+// contextFields : поля логгера ('With') вместе с полями, извлечёнными из 'ctx' извлекателями
+//                 режима 'Default' и правила 'matched'. Поля 'With' имеют приоритет над полями контекста.
+//
+//                 logger fields ('With') together with the fields extracted from 'ctx' by the extractors
+//                 of 'Default' mode and of the rule 'matched'. 'With' fields take priority over the context fields.
+//
+func (logger *Logger) contextFields(ctx context.Context, matched *rule) Fields {
+	if ctx == nil {
+		return logger.fields
+	}
+	extractors := logger.extractors
+	if matched != nil && len(matched.extractors) != 0 {
+		extractors = append(extractors[:len(extractors):len(extractors)], matched.extractors...)
+	}
+	if len(extractors) == 0 {
+		return logger.fields
+	}
+	var fields Fields
+	for _, extractor := range extractors {
+		for key, value := range extractor(ctx) {
+			if _, exist := logger.fields[key]; exist {
+				continue
+			}
+			if fields == nil {
+				fields = make(Fields, len(logger.fields)+1)
+			}
+			fields[key] = value
+		}
+	}
+	if fields == nil {
+		return logger.fields
+	}
+	for key, value := range logger.fields {
+		fields[key] = value
+	}
+	return fields
+}
+
+// TraceCtx : логирование уровня 'trace' с полями из контекста ('DefaultContext').
+//            logging level 'trace' with fields from the context ('DefaultContext').
+//
+func (logger *Logger) TraceCtx(ctx context.Context, value interface{}, modes ...Mode) {
+	logger.logging(ctx, value, LevelTrace, modes...)
+}
+
+// DebugCtx : логирование уровня 'debug' с полями из контекста ('DefaultContext').
+//            logging level 'debug' with fields from the context ('DefaultContext').
+//
+func (logger *Logger) DebugCtx(ctx context.Context, value interface{}, modes ...Mode) {
+	logger.logging(ctx, value, LevelDebug, modes...)
+}
+
+// InfoCtx : логирование уровня 'info' с полями из контекста ('DefaultContext').
+//           logging level 'info' with fields from the context ('DefaultContext').
+//
+func (logger *Logger) InfoCtx(ctx context.Context, value interface{}, modes ...Mode) {
+	logger.logging(ctx, value, LevelInfo, modes...)
+}
+
+// WarnCtx : логирование уровня 'warn' с полями из контекста ('DefaultContext').
+//           logging level 'warn' with fields from the context ('DefaultContext').
+//
+func (logger *Logger) WarnCtx(ctx context.Context, value interface{}, modes ...Mode) {
+	logger.logging(ctx, value, LevelWarn, modes...)
+}
+
+// ErrorCtx : логирование уровня 'error' с полями из контекста ('DefaultContext').
+//            logging level 'error' with fields from the context ('DefaultContext').
+//
+func (logger *Logger) ErrorCtx(ctx context.Context, value interface{}, modes ...Mode) {
+	logger.logging(ctx, value, LevelError, modes...)
+}
+
+// PanicCtx : логирование уровня 'panic' с полями из контекста ('DefaultContext').
+//            Как и 'Panic', не вызывает 'panic()'.
+//
+//            logging level 'panic' with fields from the context ('DefaultContext').
+//            Like 'Panic', it doesn't call 'panic()'.
+//
+func (logger *Logger) PanicCtx(ctx context.Context, value interface{}, modes ...Mode) {
+	logger.logging(ctx, value, LevelPanic, modes...)
+}
+
+// FatalCtx : логирование уровня 'fatal' с полями из контекста ('DefaultContext').
+//            Как и 'Fatal', не завершает процесс.
+//
+//            logging level 'fatal' with fields from the context ('DefaultContext').
+//            Like 'Fatal', it doesn't exit the process.
+//
+func (logger *Logger) FatalCtx(ctx context.Context, value interface{}, modes ...Mode) {
+	logger.logging(ctx, value, LevelFatal, modes...)
+}
@@ -0,0 +1,68 @@
+package gologster
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+type requestIDKey struct{}
+
+type captureSink struct {
+	mx      sync.Mutex
+	entries []*Entry
+}
+
+func (sink *captureSink) Output(entry *Entry, line string, param ...string) error {
+	sink.mx.Lock()
+	defer sink.mx.Unlock()
+	sink.entries = append(sink.entries, entry)
+	return nil
+}
+
+func (sink *captureSink) last() *Entry {
+	sink.mx.Lock()
+	defer sink.mx.Unlock()
+	if len(sink.entries) == 0 {
+		return nil
+	}
+	return sink.entries[len(sink.entries)-1]
+}
+
+func TestPackageContextReplace(t *testing.T) {
+	const pckg = "github.com/RobertGumpert/logster"
+	var (
+		sink    = new(captureSink)
+		calls   int
+		counter = func(ctx context.Context) Fields {
+			calls++
+			return nil
+		}
+		installers = []PackageInstaller{
+			PackageSink("capture", sink, BaseLogTemplate, SingleThreading),
+			PackageContext(ContextValue(requestIDKey{}, "request_id"), counter),
+		}
+		ctx = context.WithValue(context.Background(), requestIDKey{}, "r-1")
+	)
+	logger := Packages(map[string][]PackageInstaller{pckg: installers})
+	for i := 0; i < 5; i++ {
+		if err := logger.ReplacePackage(pckg, installers...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logger.InfoCtx(ctx, "replaced")
+	if calls != 1 {
+		t.Errorf("extractor is called %d times, want 1", calls)
+	}
+	if entry := sink.last(); entry == nil || entry.Fields["request_id"] != "r-1" {
+		t.Errorf("got %v, want field 'request_id'", entry)
+	}
+	if err := logger.ReplacePackage(pckg, installers[0]); err != nil {
+		t.Fatal(err)
+	}
+	calls = 0
+	logger.InfoCtx(ctx, "without context")
+	if entry := sink.last(); calls != 0 || entry == nil || entry.Fields["request_id"] != nil {
+		t.Errorf("extractors are kept after ReplacePackage : %d calls, %v", calls, entry)
+	}
+}
@@ -0,0 +1,224 @@
+package gologster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Encoder : создаёт строку лога вместо шаблона 'text/template'.
+//           Устанавливается методом инсталлера '.Encoder(...)'.
+//
+//           creates a log line instead of a 'text/template' template.
+//           It's set by the installer method '.Encoder(...)'.
+//
+// EXAMPLE: gologster.DefaultConsoleSimple("").Encoder(gologster.JSONEncoder())
+//
+type Encoder interface {
+	// Encode : строка лога без символа перевода строки.
+	//          log line without a line feed character.
+	//
+	// К моменту вызова 'Entry.Value' уже содержит значение пользователя в формате JSON.
+	//
+	// By the time of the call 'Entry.Value' already contains the user value in JSON format.
+	//
+	Encode(log *Entry) (string, error)
+}
+
+// JSONEncoder : JSON Lines - один JSON объект на строку. Значение пользователя
+//               встраивается как JSON значение, а не как экранированная строка:
+//
+//               JSON Lines - one JSON object per line. The user value
+//               is embedded as a JSON value, not as an escaped string:
+//
+//               {"date":"2006-01-02T15:04:05.999999999Z","level":"INFO","package":"main","func":"main","file":"app/main.go","line":"12","value":{"id":1}}
+//
+func JSONEncoder() Encoder {
+	return jsonEncoder{}
+}
+
+// LogfmtEncoder : строка в формате logfmt (key=value):
+//                 line in logfmt (key=value) format:
+//
+//                 date=2006-01-02T15:04:05.999999999Z level=INFO package=main func=main file=app/main.go line=12 value="text"
+//
+// Строковое значение пользователя выводится без JSON кавычек, остальные значения - в формате JSON.
+//
+// A string user value is output without JSON quotes, other values - in JSON format.
+//
+func LogfmtEncoder() Encoder {
+	return logfmtEncoder{}
+}
+
+type jsonEncoder struct{}
+
+// jsonLine : поля строки 'JSONEncoder' в порядке вывода.
+//            fields of the 'JSONEncoder' line in output order.
+//
+type jsonLine struct {
+	Date     string          `json:"date"`
+	Level    string          `json:"level"`
+	Package  string          `json:"package"`
+	Receiver string          `json:"receiver,omitempty"`
+	Func     string          `json:"func"`
+	File     string          `json:"file"`
+	Line     string          `json:"line"`
+	Value    json.RawMessage `json:"value"`
+	Error    string          `json:"error,omitempty"`
+	Stack    Stack           `json:"stack,omitempty"`
+	Errors   ErrorChain      `json:"errors,omitempty"`
+	Fields   Fields          `json:"fields,omitempty"`
+}
+
+// Encode : implement Encoder interface
+//
+func (encoder jsonEncoder) Encode(log *Entry) (string, error) {
+	line := jsonLine{
+		Date:     encoderDate(log),
+		Level:    log.Level,
+		Package:  log.Package,
+		Receiver: log.Receiver,
+		Func:     log.Func,
+		File:     log.ShortFile,
+		Line:     log.Line,
+		Value:    json.RawMessage(log.Value),
+		Stack:    log.Stack,
+		Errors:   log.Errors,
+		Fields:   log.Fields,
+	}
+	if log.marshalErr() != nil || !json.Valid(line.Value) {
+		line.Value = json.RawMessage("null")
+		if log.marshalErr() != nil {
+			line.Error = log.marshalErr().Error()
+		}
+	}
+	out, err := json.Marshal(line)
+	if err != nil {
+		return "", errors.New("JSONEncoder.Encode : " + err.Error())
+	}
+	return string(out), nil
+}
+
+func (encoder jsonEncoder) String() string {
+	return "json"
+}
+
+type logfmtEncoder struct{}
+
+// Encode : implement Encoder interface
+//
+func (encoder logfmtEncoder) Encode(log *Entry) (string, error) {
+	value := log.Value
+	// Только строки JSON выводятся без кавычек, 'null' остаётся как есть.
+	// Only JSON strings are output without quotes, 'null' stays as is.
+	var text string
+	if strings.HasPrefix(value, `"`) && json.Unmarshal([]byte(value), &text) == nil {
+		value = text
+	}
+	pairs := []string{
+		"date=" + logfmtValue(encoderDate(log)),
+		"level=" + logfmtValue(log.Level),
+		"package=" + logfmtValue(log.Package),
+	}
+	if log.Receiver != "" {
+		pairs = append(pairs, "receiver="+logfmtValue(log.Receiver))
+	}
+	pairs = append(pairs,
+		"func="+logfmtValue(log.Func),
+		"file="+logfmtValue(log.ShortFile),
+		"line="+logfmtValue(log.Line),
+		"value="+logfmtValue(value),
+	)
+	if log.marshalErr() != nil {
+		pairs = append(pairs, "error="+logfmtValue(log.marshalErr().Error()))
+	}
+	for _, key := range log.Fields.keys() {
+		pairs = append(pairs, logfmtKey(key)+"="+logfmtField(log.Fields[key]))
+	}
+	if len(log.Errors) != 0 {
+		pairs = append(pairs, "error_type="+logfmtValue(log.ErrorType), "errors="+logfmtValue(log.Errors.String()))
+	}
+	if len(log.Stack) != 0 {
+		pairs = append(pairs, "stack="+logfmtValue(log.Stack.String()))
+	}
+	return strings.Join(pairs, " "), nil
+}
+
+func (encoder logfmtEncoder) String() string {
+	return "logfmt"
+}
+
+// logfmtValue : значение logfmt, в кавычках, если оно пустое или содержит пробелы, '=' или '"'.
+//               logfmt value, quoted if it's empty or contains spaces, '=' or '"'.
+//
+func logfmtValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " =\"\t\r\n\\") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// logfmtKey : ключ logfmt без пробелов, '=' и '"'. | logfmt key without spaces, '=' and '"'.
+//
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// logfmtField : значение поля: строки и ошибки - текстом, остальные значения - в формате JSON.
+//               field value: strings and errors - as text, other values - in JSON format.
+//
+func logfmtField(value interface{}) string {
+	if isNilValue(value) {
+		return "null"
+	}
+	switch v := value.(type) {
+	case string:
+		return logfmtValue(v)
+	case error:
+		return logfmtValue(v.Error())
+	case fmt.Stringer:
+		return logfmtValue(v.String())
+	}
+	out, err := json.Marshal(value)
+	if err != nil {
+		return logfmtValue(fmt.Sprint(value))
+	}
+	return logfmtValue(string(out))
+}
+
+// encoderDate : дата для кодировщиков: в формате накопителя, если он задан через '.TimeFormat(...)',
+//               иначе - RFC3339 с наносекундами в UTC.
+//
+//               date for encoders: in the output format if it's set via '.TimeFormat(...)',
+//               otherwise - RFC3339 with nanoseconds in UTC.
+//
+func encoderDate(log *Entry) string {
+	if log.clock != nil {
+		return log.Date
+	}
+	return log.Time.UTC().Format(time.RFC3339Nano)
+}
+
+// encoderName : название кодировщика для 'AdminHandler'.
+//               encoder name for 'AdminHandler'.
+//
+func encoderName(encoder Encoder) string {
+	if encoder == nil {
+		return ""
+	}
+	if stringer, ok := encoder.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprintf("%T", encoder)
+}
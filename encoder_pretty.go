@@ -0,0 +1,211 @@
+package gologster
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// PrettyEncoder : строка лога для чтения человеком при локальной разработке. Уровень выделяется цветом,
+//                 пакет, функция и строка выравниваются по столбцам, структурные значения выводятся
+//                 с отступами на следующих строках:
+//
+//                 human-readable log line for local development. The level is colored,
+//                 the package, function and line are aligned in columns, structured values are output
+//                 with indentation on the following lines:
+//
+//                 15:04:05.000 INFO  github.com/user/app/usecase  Handle:42     user is created
+//                 15:04:05.000 DEBUG github.com/user/app/repo     Insert:118    {
+//                                                                                 "id": 1
+//                                                                               }
+//
+// Цвет определяется для каждого потока консоли отдельно: он включается, только если поток ('stdout',
+// 'stderr' или '.Writers(...)') - терминал и не установлена переменная окружения 'NO_COLOR'.
+// В файлы и пользовательские накопители строки выводятся без цвета.
+//
+// Color is determined for each console stream separately: it's enabled only if the stream ('stdout',
+// 'stderr' or '.Writers(...)') is a terminal and the 'NO_COLOR' environment variable isn't set.
+// Lines are output to files and user sinks without color.
+//
+func PrettyEncoder() Encoder {
+	return &prettyEncoder{
+		state: &prettyState{
+			colors: make(map[*os.File]bool),
+		},
+	}
+}
+
+// Ширина столбцов пакета и функции не превышает этих значений,
+// более длинные пакеты обрезаются слева, функции - справа.
+// The width of the package and function columns doesn't exceed these values,
+// longer packages are cut on the left, functions - on the right.
+const (
+	prettyPackageWidth = 40
+	prettyFuncWidth    = 32
+)
+
+type prettyEncoder struct {
+	color bool
+	// Общее для цветной и бесцветной копии кодировщика. | Shared by the colored and colorless copies of the encoder.
+	state *prettyState
+}
+
+type prettyState struct {
+	mx sync.Mutex
+	// Ширина столбцов растёт по мере появления более длинных значений.
+	// The column width grows as longer values appear.
+	packageWidth, fnWidth int
+	// Поддержка цвета потоками, проверяется один раз для каждого файла.
+	// Color support of the streams, it's checked once for each file.
+	colors map[*os.File]bool
+}
+
+// writerEncoder : кодировщик, вывод которого зависит от потока, в который будет записана строка.
+//                 encoder whose output depends on the stream to which the line will be written.
+//
+type writerEncoder interface {
+	forWriter(writer io.Writer) Encoder
+}
+
+// forWriter : implement writerEncoder interface
+//
+func (encoder *prettyEncoder) forWriter(writer io.Writer) Encoder {
+	color := false
+	if file, ok := writer.(*os.File); ok {
+		color = encoder.state.colorSupported(file)
+	}
+	if color == encoder.color {
+		return encoder
+	}
+	return &prettyEncoder{color: color, state: encoder.state}
+}
+
+func (state *prettyState) colorSupported(file *os.File) bool {
+	state.mx.Lock()
+	defer state.mx.Unlock()
+	color, exist := state.colors[file]
+	if !exist {
+		color = colorSupported(file)
+		state.colors[file] = color
+	}
+	return color
+}
+
+// Encode : implement Encoder interface
+//
+func (encoder *prettyEncoder) Encode(log *Entry) (string, error) {
+	date := log.Date
+	if log.clock == nil {
+		date = log.Time.Format("15:04:05.000")
+	}
+	pckg := log.Package
+	if count := utf8.RuneCountInString(pckg); count > prettyPackageWidth {
+		pckg = "…" + string([]rune(pckg)[count-prettyPackageWidth+1:])
+	}
+	function := log.Func
+	if log.Receiver != "" {
+		function = "(" + log.Receiver + ")." + function
+	}
+	location := templateTrunc(prettyFuncWidth, function) + ":" + log.Line
+	packageWidth, fnWidth := encoder.state.columns(pckg, location)
+	//
+	var line strings.Builder
+	line.WriteString(encoder.paint("gray", date))
+	line.WriteString(" ")
+	line.WriteString(encoder.paint(log.Level, templatePad(5, log.Level)))
+	line.WriteString(" ")
+	line.WriteString(encoder.paint("cyan", templatePad(packageWidth, pckg)))
+	line.WriteString("  ")
+	line.WriteString(templatePad(fnWidth, location))
+	line.WriteString("  ")
+	indent := utf8.RuneCountInString(date) + 1 + 5 + 1 + packageWidth + 2 + fnWidth + 2
+	line.WriteString(prettyValue(log.Value, strings.Repeat(" ", indent)))
+	for _, key := range log.Fields.keys() {
+		line.WriteString(" ")
+		line.WriteString(encoder.paint("gray", logfmtKey(key)+"="))
+		line.WriteString(logfmtField(log.Fields[key]))
+	}
+	if log.marshalErr() != nil {
+		line.WriteString(" ")
+		line.WriteString(encoder.paint("red", "error="+log.marshalErr().Error()))
+	}
+	for _, cause := range log.Errors {
+		line.WriteString("\n" + strings.Repeat(" ", indent))
+		line.WriteString(encoder.paint("red", cause.Type+": "+cause.Message))
+	}
+	for _, frame := range log.Stack {
+		line.WriteString("\n" + strings.Repeat(" ", indent))
+		line.WriteString(encoder.paint("gray", frame.Func+" "+frame.File+":"+strconv.Itoa(frame.Line)))
+	}
+	return line.String(), nil
+}
+
+func (encoder *prettyEncoder) String() string {
+	return "pretty"
+}
+
+// columns : текущая ширина столбцов пакета и функции. | current width of the package and function columns.
+//
+func (state *prettyState) columns(pckg, location string) (int, int) {
+	state.mx.Lock()
+	defer state.mx.Unlock()
+	if width := utf8.RuneCountInString(pckg); width > state.packageWidth {
+		state.packageWidth = width
+	}
+	if width := utf8.RuneCountInString(location); width > state.fnWidth {
+		state.fnWidth = width
+	}
+	return state.packageWidth, state.fnWidth
+}
+
+func (encoder *prettyEncoder) paint(color, value string) string {
+	if !encoder.color {
+		return value
+	}
+	return templateColor(color, value)
+}
+
+// prettyValue : строка выводится без кавычек, объекты и массивы - с отступами на следующих строках.
+//               a string is output without quotes, objects and arrays - with indentation on the following lines.
+//
+func prettyValue(value, indent string) string {
+	var text string
+	if err := json.Unmarshal([]byte(value), &text); err == nil {
+		return text
+	}
+	trimmed := strings.TrimSpace(value)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return value
+	}
+	buffer := new(bytes.Buffer)
+	if err := json.Indent(buffer, []byte(trimmed), indent, "  "); err != nil {
+		return value
+	}
+	return buffer.String()
+}
+
+// colorSupported : цвет поддерживается, если 'writer' - терминал и не установлены 'NO_COLOR' или 'TERM=dumb'.
+//                  color is supported if 'writer' is a terminal and 'NO_COLOR' or 'TERM=dumb' aren't set.
+//
+func colorSupported(writer io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	file, ok := writer.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
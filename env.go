@@ -0,0 +1,257 @@
+package gologster
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// Переменные окружения, которые 'Default', 'Packages' и 'FromConfig' применяют
+// поверх программной конфигурации:
+//
+//   GOLOGSTER_LEVEL=warn                      - уровень всех накопителей, файлов и маршрутов;
+//   GOLOGSTER_PKG_<name>_LEVEL=debug          - уровень маршрута пакета и его файлов;
+//   GOLOGSTER_FILE_<key>_PATH=/var/log/a.txt  - путь файла с ключом 'key'.
+//
+// '<name>' и '<key>' - ключ маршрута или файла в верхнем регистре, в котором все символы,
+// кроме букв и цифр, заменены на '_': "github.com/user/app/usecase/..." -> "GITHUB_COM_USER_APP_USECASE".
+// Для 'RouteFallback' используется имя "FALLBACK".
+// Каждая применённая (или отклонённая) переменная выводится в консоль при создании логгера.
+//
+// Environment variables that 'Default', 'Packages' and 'FromConfig' apply
+// on top of the programmatic configuration:
+//
+//   GOLOGSTER_LEVEL=warn                      - level of all outputs, files and routes;
+//   GOLOGSTER_PKG_<name>_LEVEL=debug          - level of the package route and its files;
+//   GOLOGSTER_FILE_<key>_PATH=/var/log/a.txt  - path of the file with the key 'key'.
+//
+// '<name>' and '<key>' are the route or file key in upper case, in which all characters
+// except letters and digits are replaced with '_': "github.com/user/app/usecase/..." -> "GITHUB_COM_USER_APP_USECASE".
+// The name "FALLBACK" is used for 'RouteFallback'.
+// Each applied (or rejected) variable is output to the console when the logger is created.
+
+const (
+	envPrefix    = "GOLOGSTER_"
+	envLevel     = envPrefix + "LEVEL"
+	envPackage   = envPrefix + "PKG_"
+	envFile      = envPrefix + "FILE_"
+	envLevelEnd  = "_LEVEL"
+	envPathEnd   = "_PATH"
+	envFallback  = "FALLBACK"
+	envReportTag = "gologster : env : "
+)
+
+// envName : имя ключа маршрута или файла в переменной окружения.
+//           name of the route or file key in an environment variable.
+//
+func envName(key string) string {
+	if key == RouteFallback {
+		return envFallback
+	}
+	var (
+		name       = make([]rune, 0, len(key))
+		underscore = true
+	)
+	for _, r := range strings.ToUpper(key) {
+		if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			name = append(name, r)
+			underscore = false
+			continue
+		}
+		if !underscore {
+			name = append(name, '_')
+			underscore = true
+		}
+	}
+	return strings.TrimSuffix(string(name), "_")
+}
+
+// applyEnv : применяет переменные окружения 'GOLOGSTER_*' и выводит отчёт в консоль.
+//            Вызывается до того, как логгер станет доступен другим горутинам.
+//
+//            applies the 'GOLOGSTER_*' environment variables and outputs a report to the console.
+//            It's called before the logger becomes available to other goroutines.
+//
+func (logger *Logger) applyEnv() {
+	variables := make(map[string]string)
+	for _, variable := range os.Environ() {
+		if !strings.HasPrefix(variable, envPrefix) {
+			continue
+		}
+		pair := strings.SplitN(variable, "=", 2)
+		if len(pair) == 2 {
+			variables[pair[0]] = pair[1]
+		}
+	}
+	if len(variables) == 0 {
+		return
+	}
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	// Общий уровень применяется первым, чтобы уровни пакетов имели приоритет.
+	// The common level is applied first so that package levels take precedence.
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == envLevel) != (names[j] == envLevel) {
+			return names[i] == envLevel
+		}
+		return names[i] < names[j]
+	})
+	for _, name := range names {
+		value := variables[name]
+		var report string
+		switch {
+		case name == envLevel:
+			report = logger.envLevel(value)
+		case strings.HasPrefix(name, envPackage) && strings.HasSuffix(name, envLevelEnd):
+			report = logger.envPackageLevel(strings.TrimSuffix(strings.TrimPrefix(name, envPackage), envLevelEnd), value)
+		case strings.HasPrefix(name, envFile) && strings.HasSuffix(name, envPathEnd):
+			report = logger.envFilePath(strings.TrimSuffix(strings.TrimPrefix(name, envFile), envPathEnd), value)
+		default:
+			report = "unknown variable"
+		}
+		out := envReportTag + name + "=" + value + " : " + report
+		_ = logger.base.output(&out)
+	}
+	logger.recalculate()
+}
+
+// envLevel : 'GOLOGSTER_LEVEL'.
+//
+func (logger *Logger) envLevel(value string) string {
+	var lvl Level
+	if err := lvl.UnmarshalText([]byte(value)); err != nil {
+		return "rejected : " + err.Error()
+	}
+	if logger.modeConsole != nil {
+		logger.modeConsole.level = lvl
+	}
+	for _, sink := range logger.sinks {
+		sink.level = lvl
+	}
+	for _, rule := range logger.pckgs.rules {
+		for i := range rule.routes {
+			rule.routes[i].level = lvl
+		}
+	}
+	logger.eachFile(func(key string, file *fileAgent) {
+		file.level = lvl
+	})
+	return "applied"
+}
+
+// envPackageLevel : 'GOLOGSTER_PKG_<name>_LEVEL'.
+//
+func (logger *Logger) envPackageLevel(name, value string) string {
+	var lvl Level
+	if err := lvl.UnmarshalText([]byte(value)); err != nil {
+		return "rejected : " + err.Error()
+	}
+	var applied []string
+	for _, rule := range logger.pckgs.rules {
+		if envName(rule.key) != name {
+			continue
+		}
+		for i := range rule.routes {
+			rule.routes[i].level = lvl
+		}
+		logger.eachFile(func(key string, file *fileAgent) {
+			if key == rule.key {
+				file.level = lvl
+			}
+		})
+		applied = append(applied, "'"+rule.key+"'")
+	}
+	if len(applied) == 0 {
+		return "rejected : route isn't exist"
+	}
+	return "applied to " + strings.Join(applied, ", ")
+}
+
+// envFilePath : 'GOLOGSTER_FILE_<key>_PATH'.
+//
+func (logger *Logger) envFilePath(name, path string) string {
+	if path == "" {
+		return "rejected : path is empty"
+	}
+	var applied []string
+	if logger.modeFileMutex != nil {
+		for _, key := range envKeys(logger.modeFileMutex.config, name) {
+			file := logger.modeFileMutex.config[key]
+			logger.modeFileMutex.newFile(key, path, logger.modeFileMutex.baseFile.settingsOf(file), file.format)
+			applied = append(applied, "'"+key+"'")
+		}
+	}
+	if logger.modeFileMulti != nil {
+		for _, key := range envKeys(logger.modeFileMulti.config, name) {
+			file := logger.modeFileMulti.config[key]
+			logger.modeFileMulti.newFile(key, path, logger.modeFileMulti.baseFile.settingsOf(file), file.format)
+			applied = append(applied, "'"+key+"'")
+		}
+	}
+	if len(applied) == 0 {
+		return "rejected : file isn't exist"
+	}
+	return "applied to " + strings.Join(applied, ", ")
+}
+
+// envKeys : ключи файлов с именем 'name' в переменной окружения.
+//           file keys with the name 'name' in an environment variable.
+//
+func envKeys(config map[string]*fileAgent, name string) []string {
+	var keys []string
+	for key := range config {
+		if envName(key) == name {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// eachFile : вызывает 'apply' для каждого ключа файла обоих файловых логгеров.
+//            calls 'apply' for each file key of both file loggers.
+//
+func (logger *Logger) eachFile(apply func(key string, file *fileAgent)) {
+	for _, config := range logger.fileConfigs() {
+		for key, file := range config {
+			apply(key, file)
+		}
+	}
+}
+
+// fileConfigs : карты ключей файлов обоих файловых логгеров.
+//               file key maps of both file loggers.
+//
+func (logger *Logger) fileConfigs() []map[string]*fileAgent {
+	var configs []map[string]*fileAgent
+	if logger.modeFileMutex != nil {
+		configs = append(configs, logger.modeFileMutex.config)
+	}
+	if logger.modeFileMulti != nil {
+		configs = append(configs, logger.modeFileMulti.config)
+	}
+	return configs
+}
+
+// recalculate : пересчитывает минимальный уровень логгера после изменения уровней.
+//               recalculates the minimum level of the logger after levels are changed.
+//
+func (logger *Logger) recalculate() {
+	logger.level = LevelFatal
+	if logger.modeConsole != nil {
+		logger.threshold(logger.modeConsole.level)
+	}
+	for _, sink := range logger.sinks {
+		logger.threshold(sink.level)
+	}
+	for _, rule := range logger.pckgs.rules {
+		for _, route := range rule.routes {
+			logger.threshold(route.level)
+		}
+	}
+	logger.eachFile(func(key string, file *fileAgent) {
+		logger.threshold(file.level)
+	})
+}
@@ -0,0 +1,69 @@
+package gologster
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Максимальная длина цепочки 'errors.Unwrap', защищает от ошибок, которые возвращают сами себя.
+// Maximum length of the 'errors.Unwrap' chain, protects against errors that return themselves.
+const maxErrorChain = 32
+
+// ErrorCause : ошибка из цепочки 'errors.Unwrap': сообщение и конкретный тип ("*os.PathError").
+//              error from the 'errors.Unwrap' chain: message and concrete type ("*os.PathError").
+//
+type ErrorCause struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+// ErrorChain : цепочка 'errors.Unwrap', начиная с самой ошибки. В шаблонах '{{.Errors}}' выводится так:
+//              chain of 'errors.Unwrap' starting from the error itself. In templates '{{.Errors}}' is output as:
+//
+//              *fmt.wrapError: create user: open users.db: permission denied <- *os.PathError: open users.db: permission denied
+//
+type ErrorChain []ErrorCause
+
+// String : implement fmt.Stringer interface
+//
+func (chain ErrorChain) String() string {
+	causes := make([]string, 0, len(chain))
+	for _, cause := range chain {
+		causes = append(causes, cause.Type+": "+cause.Message)
+	}
+	return strings.Join(causes, " <- ")
+}
+
+// errorChain : цепочка 'errors.Unwrap' ошибки 'err'. | 'errors.Unwrap' chain of the error 'err'.
+//
+func errorChain(err error) ErrorChain {
+	var chain ErrorChain
+	for !isNilValue(err) && len(chain) < maxErrorChain {
+		chain = append(chain, ErrorCause{
+			Message: err.Error(),
+			Type:    fmt.Sprintf("%T", err),
+		})
+		err = errors.Unwrap(err)
+	}
+	return chain
+}
+
+// isNilValue : значение nil, в том числе типизированный nil ('var err *os.PathError'),
+//              у которого нельзя вызывать 'Error()'.
+//
+//              nil value, including typed nil ('var err *os.PathError'),
+//              on which 'Error()' can't be called.
+//
+func isNilValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+	return false
+}
@@ -1,10 +1,10 @@
 package main
 
 import (
+	"context"
 	"github.com/RobertGumpert/gologster"
 	"path/filepath"
 	"runtime"
-	"time"
 )
 
 func getRoot() string {
@@ -23,7 +23,7 @@ func main() {
 
 	logger.Info("App has terminated!", gologster.OptionConsole(), gologster.GoOptionFileMulti("log_1"))
 
-	time.Sleep(5*time.Second)
+	_ = logger.Close(context.Background())
 }
 
 func createLogger(root string) *gologster.Logger {
@@ -1,13 +1,13 @@
 package main
 
 import (
+	"context"
 	"./mypackage"
 	urep "./repository/user"
 	ucase "./usecase/user"
 	"github.com/RobertGumpert/gologster"
 	"path/filepath"
 	"runtime"
-	"time"
 )
 
 func getRoot() string {
@@ -35,7 +35,7 @@ func main() {
 
 	logger.Info("App has terminated!")
 
-	time.Sleep(5 * time.Second)
+	_ = logger.Close(context.Background())
 }
 
 func createLogger(root string) *gologster.Logger {
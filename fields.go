@@ -0,0 +1,82 @@
+package gologster
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Fields : поля дочернего логгера ('Logger.With'), которые добавляются к каждому его логу.
+//          В шаблонах доступны как '{{.Fields.user_id}}', 'JSONEncoder' выводит их объектом "fields".
+//
+//          fields of a child logger ('Logger.With') that are added to each of its logs.
+//          In templates they are available as '{{.Fields.user_id}}', 'JSONEncoder' outputs them as the "fields" object.
+//
+type Fields map[string]interface{}
+
+// MarshalJSON : implement json.Marshaler interface
+//
+// Ошибки выводятся сообщением, а не "{}".
+//
+// Errors are output as a message rather than "{}".
+//
+func (fields Fields) MarshalJSON() ([]byte, error) {
+	values := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		if err, ok := value.(error); ok && !isNilValue(err) {
+			value = err.Error()
+		}
+		values[key] = value
+	}
+	return json.Marshal(values)
+}
+
+// keys : ключи полей в порядке сортировки. | field keys in sorted order.
+//
+func (fields Fields) keys() []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// With : дочерний логгер, который добавляет к каждому логу поля 'keyvals' - пары ключ, значение:
+//        logger.With("user_id", 42, "request_id", id). Ключи, не являющиеся строками, приводятся
+//        к строке через 'fmt.Sprint', ключ без значения получает значение nil. Поля родителя
+//        сохраняются, одинаковые ключи заменяются.
+//
+//        Дочерний логгер использует маршруты, накопители и файлы родителя, поэтому его можно
+//        создавать на каждый запрос. 'Close' любого из них закрывает оба.
+//
+//        child logger that adds the fields 'keyvals' - key, value pairs - to each log:
+//        logger.With("user_id", 42, "request_id", id). Keys that aren't strings are converted
+//        to a string via 'fmt.Sprint', a key without a value gets the value nil. Parent fields
+//        are kept, the same keys are replaced.
+//
+//        The child logger uses the routes, outputs and files of the parent, so it can
+//        be created per request. 'Close' of either of them closes both.
+//
+func (logger *Logger) With(keyvals ...interface{}) *Logger {
+	fields := make(Fields, len(logger.fields)+(len(keyvals)+1)/2)
+	for key, value := range logger.fields {
+		fields[key] = value
+	}
+	for i := 0; i < len(keyvals); i += 2 {
+		key, ok := keyvals[i].(string)
+		if !ok {
+			key = fmt.Sprint(keyvals[i])
+		}
+		var value interface{}
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+		fields[key] = value
+	}
+	return &Logger{
+		loggerCore: logger.loggerCore,
+		callerSkip: logger.callerSkip,
+		fields:     fields,
+	}
+}
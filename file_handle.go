@@ -0,0 +1,121 @@
+package gologster
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// reopenInterval : как часто проверяется, что открытый файл не был перемещён или удалён.
+//                  how often it is checked that the open file hasn't been moved or deleted.
+//
+const reopenInterval = time.Second
+
+// fileHandle : открытый файл лога, который переиспользуется для всех строк.
+//              open log file that is reused for all lines.
+//
+// Файл и его директории создаются при первой записи. Если файл был перемещён
+// или удалён (например, внешним logrotate), он будет открыт заново по тому же пути.
+//
+// The file and its directories are created on the first write. If the file has been moved
+// or deleted (for example, by an external logrotate), it will be reopened at the same path.
+//
+type fileHandle struct {
+	path          string
+	perm, dirPerm os.FileMode
+	mx            sync.Mutex
+	file          *os.File
+	checked       time.Time
+}
+
+// newFileHandle : constructor
+//
+func newFileHandle(path string, perm, dirPerm os.FileMode) *fileHandle {
+	handle := new(fileHandle)
+	handle.path = path
+	handle.perm = perm
+	handle.dirPerm = dirPerm
+	return handle
+}
+
+// Write : implement io.Writer
+//
+func (handle *fileHandle) Write(p []byte) (int, error) {
+	handle.mx.Lock()
+	defer handle.mx.Unlock()
+	if err := handle.open(); err != nil {
+		return 0, err
+	}
+	n, err := handle.file.Write(p)
+	if err != nil {
+		// Следующая запись откроет файл заново.
+		// The next write will reopen the file.
+		_ = handle.file.Close()
+		handle.file = nil
+	}
+	return n, err
+}
+
+// WriteString : implement io.StringWriter
+//
+func (handle *fileHandle) WriteString(s string) (int, error) {
+	return handle.Write([]byte(s))
+}
+
+// open : открывает файл, если он ещё не открыт или был перемещён, удалён.
+//        opens the file if it isn't open yet or has been moved, deleted.
+//
+func (handle *fileHandle) open() error {
+	if handle.file != nil {
+		if time.Since(handle.checked) < reopenInterval {
+			return nil
+		}
+		handle.checked = time.Now()
+		if handle.same() {
+			return nil
+		}
+		_ = handle.file.Close()
+		handle.file = nil
+	}
+	if err := os.MkdirAll(filepath.Dir(handle.path), handle.dirPerm); err != nil {
+		return errors.New("fileHandle.open : " + err.Error())
+	}
+	file, err := os.OpenFile(handle.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, handle.perm)
+	if err != nil {
+		return errors.New("fileHandle.open : " + err.Error())
+	}
+	handle.file = file
+	handle.checked = time.Now()
+	return nil
+}
+
+// same : проверяет, что по пути всё ещё находится открытый файл.
+//        checks that the open file is still located at the path.
+//
+func (handle *fileHandle) same() bool {
+	opened, err := handle.file.Stat()
+	if err != nil {
+		return false
+	}
+	current, err := os.Stat(handle.path)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(opened, current)
+}
+
+// Close : закрывает файл. Следующая запись откроет его заново.
+//         closes the file. The next write will reopen it.
+//
+func (handle *fileHandle) Close() error {
+	handle.mx.Lock()
+	defer handle.mx.Unlock()
+	if handle.file == nil {
+		return nil
+	}
+	err := handle.file.Close()
+	handle.file = nil
+	return err
+}
@@ -3,24 +3,33 @@ package gologster
 import (
 	"encoding/json"
 	"errors"
-	"log"
+	"io"
 	"os"
 	"strings"
+	"sync"
 )
 
 // loggerBase : определяет базовое поведение любого логгера | defines the base behavior of any logger
 //
-// Для вывода использует стандартный пакет 'callingMode' в консоль.
+// Для вывода в консоль использует собственный поток 'writer' (по умолчанию 'os.Stdout'),
+// не изменяя состояние стандартного пакета 'log'.
 //
-// Uses the standard 'callingMode' package for output to the console.
+// Uses its own stream 'writer' (by default 'os.Stdout') for output to the console,
+// without changing the state of the standard package 'log'.
 //
 type loggerBase struct {
+	// Поток вывода в консоль и мьютекс, защищающий его от перемешивания строк.
+	// Console output stream and the mutex protecting it from interleaving lines.
+	mx     sync.Mutex
+	writer io.Writer
 }
 
 // newBase() : constructor
 //
 func newBase() *loggerBase {
-	return new(loggerBase)
+	logger := new(loggerBase)
+	logger.writer = os.Stdout
+	return logger
 }
 
 // add : implement iLogger interface
@@ -30,7 +39,7 @@ func newBase() *loggerBase {
 //
 // Types that embed a given type can define behavior on their own.
 //
-func (logger *loggerBase) add(log *logData, param ...string) {
+func (logger *loggerBase) add(log *Entry, param ...string) {
 	return
 }
 
@@ -43,11 +52,17 @@ func (logger *loggerBase) add(log *logData, param ...string) {
 // Doesn't use parameters.
 // Types that embed a given type can define behavior on their own.
 //
-func (logger *loggerBase) createOutputString(log *logData, param ...string) (*string, error) {
+func (logger *loggerBase) createOutputString(log *Entry, param ...string) (*string, error) {
 	var (
 		out = ""
 	)
-	bytes, err := json.Marshal(log.UserDataOriginal)
+	value := log.UserDataOriginal
+	// 'json.Marshal' выводит большинство ошибок как "{}", поэтому для них используется сообщение.
+	// 'json.Marshal' outputs most errors as "{}", so the message is used for them.
+	if log.Error != nil {
+		value = log.Error.Error()
+	}
+	bytes, err := json.Marshal(value)
 	if err != nil {
 		e := strings.Join([]string{
 			"error in json.Value(value)='",
@@ -70,10 +85,20 @@ func (logger *loggerBase) createOutputString(log *logData, param ...string) (*st
 // Types that embed a given type can define behavior on their own.
 //
 func (logger *loggerBase) output(out *string, param ...string) error {
-	log.SetOutput(os.Stdout)
-	log.SetFlags(log.Flags() &^ (log.Ldate | log.Ltime))
-	log.Println(*out)
-	return nil
+	return logger.write(logger.writer, out)
+}
+
+// write : выводит строку в 'writer' под мьютексом консоли, чтобы строки
+//         разных потоков ('stdout', 'stderr') не перемешивались.
+//
+//         outputs the line to 'writer' under the console mutex so that lines
+//         of different streams ('stdout', 'stderr') don't interleave.
+//
+func (logger *loggerBase) write(writer io.Writer, out *string) error {
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	_, err := io.WriteString(writer, *out+"\n")
+	return err
 }
 
 // errorOutput : implement iLogger interface
@@ -84,9 +109,15 @@ func (logger *loggerBase) output(out *string, param ...string) error {
 // Types that embed a given type can define behavior on their own.
 //
 func (logger *loggerBase) errorOutput(out *string, err error) {
+	_ = logger.output(errorLine(out, err))
+}
+
+// errorLine : строка лога вместе с ошибкой. | log line along with the error.
+//
+func errorLine(out *string, err error) *string {
 	update := strings.Join([]string{
 		*out,
 		"error=[" + err.Error() + "];",
 	}, "")
-	_ = logger.output(&update)
+	return &update
 }
@@ -1,7 +1,8 @@
 package gologster
 
 import (
-	"text/template"
+	"io"
+	"os"
 )
 
 // loggerBaseConsole : определяет базовое поведение логгера в консоль| defines the base behavior of the logger to the console
@@ -9,21 +10,89 @@ import (
 type loggerBaseConsole struct {
 	// Объект базового логгера, со стандартным поведением.
 	// Basic logger object, with standard behavior.
-	base     *loggerBase
-	tmpl     *template.Template
-	packages map[string]struct{}
+	base *loggerBase
+	// Формат и потоки логов без маршрута пакета ('OptionConsole', 'Default').
+	// Format and streams of logs without a package route ('OptionConsole', 'Default').
+	format *consoleFormat
+	// Пакет -> формат и потоки инсталлера 'PackageConsoleSimple' этого пакета.
+	// Package -> format and streams of the 'PackageConsoleSimple' installer of this package.
+	packages map[string]*consoleFormat
 }
 
 // newBaseConsole : constructor
 //
-func newBaseConsole(base *loggerBase, packages map[string]struct{}, tmpl *template.Template) *loggerBaseConsole {
+func newBaseConsole(base *loggerBase, packages map[string]*consoleFormat, format *consoleFormat) *loggerBaseConsole {
 	logger := new(loggerBaseConsole)
 	logger.base = base
-	logger.tmpl = tmpl
+	logger.format = format
 	logger.packages = packages
 	return logger
 }
 
+// consoleFormat : формат строки лога вместе с потоками инсталлера консоли.
+//                 log line format together with the streams of the console installer.
+//
+type consoleFormat struct {
+	*outputFormat
+
+	// Логи уровня 'stderrLevel' и выше выводятся в 'stderr', остальные - в 'stdout'.
+	// Logs of the level 'stderrLevel' and above are output to 'stderr', the rest - to 'stdout'.
+	stdout, stderr io.Writer
+	stderrLevel    Level
+}
+
+// newConsoleFormat : constructor
+//
+// Потоки и уровень 'stderr', не указанные в настройках инсталлера, берутся по умолчанию,
+// а не у других пакетов.
+//
+// The streams and the 'stderr' level that aren't specified in the installer settings are taken by default,
+// not from other packages.
+//
+func newConsoleFormat(base *loggerBase, format *outputFormat, setup *settings) *consoleFormat {
+	console := &consoleFormat{
+		outputFormat: format,
+		stdout:       base.writer,
+		stderr:       os.Stderr,
+		stderrLevel:  LevelError,
+	}
+	if setup.stdout != nil {
+		console.stdout = setup.stdout
+	}
+	if setup.stderr != nil {
+		console.stderr = setup.stderr
+	}
+	if setup.stderrLevel != nil {
+		console.stderrLevel = *setup.stderrLevel
+	}
+	return console
+}
+
+// writer : поток для логов уровня 'lvl'. | stream for logs of the level 'lvl'.
+//
+func (format *consoleFormat) writer(lvl Level) io.Writer {
+	if lvl >= format.stderrLevel {
+		return format.stderr
+	}
+	return format.stdout
+}
+
+// formatOf : формат и потоки маршрута лога. | format and streams of the log route.
+//
+func (logger *loggerBaseConsole) formatOf(log *Entry) *consoleFormat {
+	if pckg, exist := logger.packages[log.Route]; exist {
+		return pckg
+	}
+	return logger.format
+}
+
+// outputEntry : выводит строку лога 'log' в 'stderr', если уровень не ниже 'stderrLevel', иначе в 'stdout'.
+//               outputs the line of the log 'log' to 'stderr' if the level isn't lower than 'stderrLevel', otherwise to 'stdout'.
+//
+func (logger *loggerBaseConsole) outputEntry(out *string, log *Entry) error {
+	return logger.base.write(logger.formatOf(log).writer(log.Lvl), out)
+}
+
 // add : implement iLogger interface
 //
 // Поведение определяется самостоятельно типами,
@@ -32,7 +101,7 @@ func newBaseConsole(base *loggerBase, packages map[string]struct{}, tmpl *templa
 // The behavior is determined independently by the types that
 // embed the given type in themselves.
 //
-func (logger *loggerBaseConsole) add(log *logData, param ...string) {
+func (logger *loggerBaseConsole) add(log *Entry, param ...string) {
 	return
 }
 
@@ -45,9 +114,10 @@ func (logger *loggerBaseConsole) add(log *logData, param ...string) {
 // The behavior is defined by the base logger 'loggerBase'.
 // Types that embed a given type can define behavior on their own.
 //
-func (logger *loggerBaseConsole) createOutputString(log *logData, param ...string) (*string, error) {
-	out := log.filledTemplate(logger.tmpl)
-	return out, nil
+func (logger *loggerBaseConsole) createOutputString(log *Entry, param ...string) (*string, error) {
+	_ = log.marshal(logger.base)
+	format := logger.formatOf(log)
+	return format.renderTo(log, format.writer(log.Lvl))
 }
 
 // output : implement iLogger interface
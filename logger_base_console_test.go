@@ -0,0 +1,29 @@
+package gologster
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPackageConsoleStreams(t *testing.T) {
+	const pckg = "github.com/RobertGumpert/logster"
+	var stdout, stderr, other bytes.Buffer
+	logger := Packages(map[string][]PackageInstaller{
+		pckg: {PackageConsoleSimple("{{.Level}}", SingleThreading).Writers(&stdout, &stderr).Stderr(LevelWarn)},
+		// Инсталлер другого пакета выполняется позже и не меняет потоки 'pckg'.
+		// The installer of another package is executed later and doesn't change the 'pckg' streams.
+		"~^example.com/": {PackageConsoleSimple("{{.Level}}", SingleThreading).Writers(&other, &other).Stderr(LevelFatal)},
+	})
+	logger.Info("info")
+	logger.Warn("warn")
+	if got := strings.TrimSpace(stdout.String()); got != "INFO" {
+		t.Errorf("stdout : got %q, want INFO", got)
+	}
+	if got := strings.TrimSpace(stderr.String()); got != "WARN" {
+		t.Errorf("stderr : got %q, want WARN", got)
+	}
+	if other.Len() != 0 {
+		t.Errorf("other package stream : got %q", other.String())
+	}
+}
@@ -2,9 +2,8 @@ package gologster
 
 import (
 	"errors"
-	"os"
 	"strings"
-	"text/template"
+	"sync"
 )
 
 // fileAgent : определяет параметры, каналы и так далее,
@@ -16,8 +15,27 @@ import (
 //			   embed this type (loggerBaseFile) when working with a file.
 //
 type fileAgent struct {
+	// Количество строк в очереди и в процессе записи,
+	// количество отброшенных строк и строк, записанных в файл переполнения (используются атомарно).
+	// Number of lines in the queue and being written,
+	// number of dropped lines and lines written to the overflow file (used atomically).
+	pending, dropped, spilled int64
+
 	path    string
 	channel chan *string
+	// Сигнал горутине-читателю о завершении работы и подтверждение завершения.
+	// Signal to the reader goroutine to stop and confirmation of stop.
+	quit, done chan struct{}
+	// Минимальный уровень логов для файла.
+	// The minimum level of logs for the file.
+	level Level
+	// Шаблон, кодировщик и формат времени инсталлера, создавшего ключ.
+	// Template, encoder and time format of the installer that created the key.
+	format *outputFormat
+	// Параметры очереди и файл переполнения для 'OverflowSpill'.
+	// Queue parameters and overflow file for 'OverflowSpill'.
+	queue Queue
+	spill *fileHandle
 }
 
 // loggerBaseFile : определяет базовое поведение логгера в файл| defines the base behavior of the logger to the file
@@ -29,22 +47,170 @@ type loggerBaseFile struct {
 
 	// key -> value : "sql" -> "~/home/dir/log_sql.txt"
 	config map[string]string
-	tmpl   *template.Template
+	// Формат логов, для которых ключ файла не найден.
+	// Format of logs for which the file key isn't found.
+	format *outputFormat
+
+	// path -> zipper : ротация файлов.
+	// path -> zipper : file rotation.
+	zippers map[string]*zipper
+
+	// path -> fileHandle : открытые файлы.
+	// path -> fileHandle : open files.
+	handles map[string]*fileHandle
+
+	// path -> количество ключей 'loggerFileMutex' и 'loggerFileMultithreading' (включая
+	// ещё дописываемые очереди), которые пишут в файл. Оба логгера могут использовать один объект.
+	// path -> number of 'loggerFileMutex' and 'loggerFileMultithreading' keys (including
+	// queues still being written out) that write to the file. Both loggers can use the same object.
+	users map[string]int
+
+	// Защищает карты 'config', 'zippers' и 'handles', так как горутины-читатели
+	// 'loggerFileMultithreading' обращаются к ним без блокировки 'Logger'.
+	// Protects the 'config', 'zippers' and 'handles' maps, since the reader goroutines
+	// of 'loggerFileMultithreading' access them without the 'Logger' lock.
+	mx sync.RWMutex
 }
 
 // newBaseFile : constructor
 //
-func newBaseFile(base *loggerBase, config map[string]string, tmpl *template.Template) *loggerBaseFile {
+func newBaseFile(base *loggerBase, config map[string]string, format *outputFormat) *loggerBaseFile {
 	logger := new(loggerBaseFile)
 	logger.base = base
 	logger.config = config
-	logger.tmpl = tmpl
+	logger.format = format
+	logger.zippers = make(map[string]*zipper)
+	logger.handles = make(map[string]*fileHandle)
+	logger.users = make(map[string]int)
 	return logger
 }
 
+// register : создаёт открытый файл и ротацию для пути 'path' с настройками инсталлера.
+//            Каждый вызов должен завершаться вызовом 'release'.
+//
+//            creates an open file and rotation for the path 'path' with the installer settings.
+//            Each call must be completed with a call to 'release'.
+//
+func (logger *loggerBaseFile) register(path string, setup *settings) {
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	logger.users[path]++
+	if _, exist := logger.handles[path]; !exist {
+		logger.handles[path] = newFileHandle(path, setup.perm, setup.dirPerm)
+	}
+	if setup.rotation != nil {
+		logger.rotate(path, *setup.rotation)
+	}
+}
+
+// handle : открытый файл для пути 'path'.
+//          open file for the path 'path'.
+//
+func (logger *loggerBaseFile) handle(path string) (*fileHandle, error) {
+	logger.mx.RLock()
+	defer logger.mx.RUnlock()
+	handle, exist := logger.handles[path]
+	if !exist {
+		return nil, errors.New("loggerBaseFile.handle file isn't registered by path : '" + path + "'")
+	}
+	return handle, nil
+}
+
+// close : закрывает все открытые файлы.
+//         closes all open files.
+//
+func (logger *loggerBaseFile) close() error {
+	logger.mx.RLock()
+	defer logger.mx.RUnlock()
+	var errs []string
+	for path, handle := range logger.handles {
+		if err := handle.Close(); err != nil {
+			errs = append(errs, path+" : "+err.Error())
+		}
+	}
+	if len(errs) != 0 {
+		return errors.New("loggerBaseFile.close : " + strings.Join(errs, "::"))
+	}
+	return nil
+}
+
+// bind : связывает ключ файла с путём. | binds the file key to the path.
+//
+func (logger *loggerBaseFile) bind(key, path string) {
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	logger.config[key] = path
+}
+
+// unbind : удаляет ключ файла. | removes the file key.
+//
+func (logger *loggerBaseFile) unbind(key string) {
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	delete(logger.config, key)
+}
+
+// files : копия карты ключей файлов. | copy of the file keys map.
+//
+func (logger *loggerBaseFile) files() map[string]string {
+	logger.mx.RLock()
+	defer logger.mx.RUnlock()
+	files := make(map[string]string, len(logger.config))
+	for key, path := range logger.config {
+		files[key] = path
+	}
+	return files
+}
+
+// release : закрывает файл 'path' и удаляет его ротацию, если ни один ключ обоих файловых
+//           логгеров больше не пишет в него.
+//
+//           closes the file 'path' and removes its rotation if no key of both file loggers
+//           writes to it anymore.
+//
+func (logger *loggerBaseFile) release(path string) error {
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	if logger.users[path]--; logger.users[path] > 0 {
+		return nil
+	}
+	delete(logger.users, path)
+	handle, exist := logger.handles[path]
+	if !exist {
+		return nil
+	}
+	delete(logger.handles, path)
+	delete(logger.zippers, path)
+	return handle.Close()
+}
+
+// settingsOf : настройки, с которыми был создан ключ файла 'file'.
+//              settings with which the file key 'file' was created.
+//
+func (logger *loggerBaseFile) settingsOf(file *fileAgent) *settings {
+	setup := newSettings()
+	setup.level = file.level
+	if file.queue.Capacity > 0 {
+		setup.queue = file.queue
+		if setup.queue.SpillPath == file.path+".overflow" {
+			setup.queue.SpillPath = ""
+		}
+	}
+	logger.mx.RLock()
+	defer logger.mx.RUnlock()
+	if handle, exist := logger.handles[file.path]; exist {
+		setup.perm, setup.dirPerm = handle.perm, handle.dirPerm
+	}
+	if z, exist := logger.zippers[file.path]; exist {
+		rotation := z.rotation
+		setup.rotation = &rotation
+	}
+	return setup
+}
+
 // getParams : проверяет наличие только одного параметра - ключ файла. | checks for only one parameter - the file key.
 //
-func (logger *loggerBaseFile) getParams(log *logData, param ...string) (error, string) {
+func (logger *loggerBaseFile) getParams(log *Entry, param ...string) (error, string) {
 	var (
 		key = ""
 	)
@@ -73,7 +239,7 @@ func (logger *loggerBaseFile) getParams(log *logData, param ...string) (error, s
 // The behavior is determined independently by the types that
 // embed the given type in themselves.
 //
-func (logger *loggerBaseFile) add(log *logData, param ...string) {
+func (logger *loggerBaseFile) add(log *Entry, param ...string) {
 	return
 }
 
@@ -86,47 +252,41 @@ func (logger *loggerBaseFile) add(log *logData, param ...string) {
 // The behavior is defined by the base logger 'logger Basic'.
 // Types that embed a given type can define behavior on their own.
 //
-func (logger *loggerBaseFile) createOutputString(log *logData, param ...string) (*string, error) {
-	out := log.filledTemplate(logger.tmpl)
-	return out, nil
+func (logger *loggerBaseFile) createOutputString(log *Entry, param ...string) (*string, error) {
+	return logger.render(log, nil)
+}
+
+// render : строка лога в формате ключа файла 'file', если он найден, иначе в формате по умолчанию.
+//          log line in the format of the file key 'file' if it's found, otherwise in the default format.
+//
+func (logger *loggerBaseFile) render(log *Entry, file *fileAgent) (*string, error) {
+	_ = log.marshal(logger.base)
+	if file != nil && file.format != nil {
+		return file.format.render(log)
+	}
+	return logger.format.render(log)
 }
 
 // output : implement iLogger interface
 //
 // Передаваемый массив параметров состоит
 // только из одного элмента - путь до файла.
-// Запись производится без буффера.
+// Запись производится без буффера в открытый файл.
 // Типы, которые встраивают в себя данный тип, могут самостоятельно
 // определять поведение.
 //
 // The passed parameter array consists
 // of only one element - the path to the file.
-// Recording is performed without a buffer.
+// Recording is performed without a buffer to the open file.
 // Types that embed a given type can define behavior on their own.
 //
 func (logger *loggerBaseFile) output(out *string, param ...string) error {
-	var (
-		path      = param[0]
-		closeFile = func(file *os.File, err error) error {
-			errFile := file.Close()
-			if errFile != nil {
-				err = errors.New(strings.Join([]string{
-					err.Error(),
-					errFile.Error(),
-				}, "::"))
-			}
-			return err
-		}
-	)
-	file, err := os.OpenFile(path, os.O_APPEND, 0666)
+	handle, err := logger.handle(param[0])
 	if err != nil {
-		return closeFile(file, nil)
+		return err
 	}
-	_, err = file.WriteString(string(*out))
-	if err != nil {
-		return closeFile(file, nil)
-	}
-	return nil
+	_, err = handle.WriteString(*out)
+	return err
 }
 
 // createOutputString : implement iLogger interface
@@ -1,19 +1,21 @@
 package gologster
 
-import "strings"
-
 // loggerConsoleSimple : определяет поведение логгера в консоль в однопоточном режиме | defines the behavior of the logger to the console in single-threaded mode
 //
-// Для вывода использует стандартный пакет 'callingMode' в консоль.
+// Для вывода в консоль использует поток базового логгера 'loggerBase'.
 // Вывод выполняется в той же горутине (потоке), где был вызван 'loggerConsoleSimple.add()'.
 //
-// Uses the standard 'callingMode' package for output to the console.
+// Uses the stream of the base logger 'loggerBase' for output to the console.
 // The output is executed in the same goroutine (thread) where 'loggerConsoleSimple.add ()' was called.
 //
 type loggerConsoleSimple struct {
 	// Базовый объект работы с консолью.
 	// Basic object of working with the console.
 	baseConsole *loggerBaseConsole
+
+	// Минимальный уровень логов, выводимых в консоль.
+	// The minimum level of logs output to the console.
+	level Level
 }
 
 // newLoggerConsoleSimple : constructor
@@ -21,34 +23,27 @@ type loggerConsoleSimple struct {
 func newLoggerConsoleSimple(baseConsole *loggerBaseConsole) *loggerConsoleSimple {
 	logger := new(loggerConsoleSimple)
 	logger.baseConsole = baseConsole
+	logger.level = LevelTrace
 	return logger
 }
 
 // add : implement iLogger interface
 //
-func (logger *loggerConsoleSimple) add(log *logData, param ...string) {
-	performOutput := func(log *logData, logger *loggerConsoleSimple) {
+func (logger *loggerConsoleSimple) add(log *Entry, param ...string) {
+	performOutput := func(log *Entry, logger *loggerConsoleSimple) {
 		out, err := logger.createOutputString(log)
 		if err != nil {
-			logger.errorOutput(out, err)
+			_ = logger.baseConsole.outputEntry(errorLine(out, err), log)
 			return
 		}
-		_ = logger.output(out)
+		_ = logger.baseConsole.outputEntry(out, log)
 	}
-	if log.IsOption {
-		performOutput(log, logger)
+	if log.Lvl < logger.level {
 		return
 	}
-	for pckg, _ := range logger.baseConsole.packages {
-		if strings.Contains(log.Package, pckg) {
-			performOutput(log, logger)
-			break
-		}
-	}
-	//if _, exist := logger.baseConsole.packages[log.Package]; exist {
-	//	performOutput(log, logger)
-	//	return
-	//}
+	// Маршрут лога уже выбран в 'Logger', поэтому вывод выполняется и для опций, и для пакетов.
+	// The log route has already been chosen in 'Logger', so the output is performed for both options and packages.
+	performOutput(log, logger)
 }
 
 // add : implement iLogger interface
@@ -57,7 +52,7 @@ func (logger *loggerConsoleSimple) add(log *logData, param ...string) {
 //
 // The behavior is defined by the base logger 'loggerBaseConsole'.
 //
-func (logger *loggerConsoleSimple) createOutputString(log *logData, param ...string) (*string, error) {
+func (logger *loggerConsoleSimple) createOutputString(log *Entry, param ...string) (*string, error) {
 	return logger.baseConsole.createOutputString(log, param...)
 }
 
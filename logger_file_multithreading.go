@@ -1,17 +1,18 @@
 package gologster
 
 import (
-	"bufio"
 	"errors"
-	"os"
 	"runtime"
-	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // loggerFileMultithreading : логгер в файл с использованием очереди на запись. | logger to file using write queue.
 //
 // Идея заключается в том, что для каждого из файлов создаётся
 // буфферизированный канал на 1000 элементов (строк, которые надо записать в файл).
+// Размер и поведение при переполнении задаются через '.Queue(Queue{...})'.
 // Существует только одна горутина-читатель 'loggerFileMultithreading.receiver()',
 // для этого канала, которая имеет право вызвать функцию записи в файл, что
 // гарантирует то, что не возникнет ситуация гонки.
@@ -22,6 +23,7 @@ import (
 //
 // The idea is that for each of the files
 // a buffered channel is created with 1000 elements (lines to be written to the file).
+// The size and the overflow behavior are set via '.Queue(Queue{...})'.
 // There is only one goroutine-reader 'loggerFileMultithreading.receiver()',
 // for this channel, which has the right to call the function of writing to the file,
 // which ensures that there is no race situation.
@@ -32,28 +34,57 @@ import (
 //
 type loggerFileMultithreading struct {
 	baseFile *loggerBaseFile
-	config   map[string]fileAgent
+	config   map[string]*fileAgent
+
+	// Удалённые файлы, очереди которых ещё дописываются.
+	// Removed files whose queues are still being written.
+	mx      sync.Mutex
+	retired map[*fileAgent]struct{}
 }
 
 // newLoggerFileMultithreading : constructor
 //
-func newLoggerFileMultithreading(baseFile *loggerBaseFile) *loggerFileMultithreading {
+func newLoggerFileMultithreading(baseFile *loggerBaseFile, setup *settings, format *outputFormat) *loggerFileMultithreading {
 	logger := new(loggerFileMultithreading)
-	logger.config = make(map[string]fileAgent, 0)
+	logger.config = make(map[string]*fileAgent, 0)
+	logger.retired = make(map[*fileAgent]struct{})
 	logger.baseFile = baseFile
-	for key, path := range baseFile.config {
-		logger.newFile(key, path)
+	for key, path := range baseFile.files() {
+		logger.newFile(key, path, setup, format)
 	}
 	return logger
 }
 
-func (logger *loggerFileMultithreading) newFile(key, path string) {
-	if _, exist := logger.config[key]; !exist {
-		logger.baseFile.config[key] = path
+// newFile : добавляет ключ файла и запускает его горутину-читателя.
+//           Очередь существующего ключа дописывается и заменяется новой.
+//
+//           adds a file key and starts its reader goroutine.
+//           The queue of an existing key is written out and replaced by a new one.
+//
+func (logger *loggerFileMultithreading) newFile(key, path string, setup *settings, format *outputFormat) {
+	if _, exist := logger.config[key]; exist {
+		logger.removeFile(key)
+	}
+	logger.baseFile.bind(key, path)
+	logger.baseFile.register(path, setup)
+	queue := setup.queue
+	if queue.Capacity <= 0 {
+		queue.Capacity = defaultQueueCapacity
 	}
-	file := fileAgent{
+	file := &fileAgent{
 		path:    path,
-		channel: make(chan *string, 1000),
+		channel: make(chan *string, queue.Capacity),
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+		level:   setup.level,
+		format:  format,
+		queue:   queue,
+	}
+	if queue.Overflow == OverflowSpill {
+		if queue.SpillPath == "" {
+			file.queue.SpillPath = path + ".overflow"
+		}
+		file.spill = newFileHandle(file.queue.SpillPath, setup.perm, setup.dirPerm)
 	}
 	logger.config[key] = file
 	go logger.receiver(file)
@@ -62,13 +93,134 @@ func (logger *loggerFileMultithreading) newFile(key, path string) {
 // receiver : итерируется по каналу, созданному для конкретного файла.
 //			  iterates over the pipe created for the specific file.
 //
-func (logger *loggerFileMultithreading) receiver(file fileAgent) {
-	for outputString := range file.channel {
-		runtime.Gosched()
-		err := logger.output(outputString, file.path)
-		if err != nil {
-			logger.errorOutput(outputString, err)
+// Завершается после закрытия канала 'quit' (строки, оставшиеся
+// в очереди к этому моменту, не записываются) или после того,
+// как дописаны все строки закрытого канала 'channel'.
+//
+// Exits after the 'quit' channel is closed (lines remaining
+// in the queue at this moment aren't written) or after
+// all lines of the closed 'channel' are written.
+//
+func (logger *loggerFileMultithreading) receiver(file *fileAgent) {
+	defer close(file.done)
+	for {
+		select {
+		case outputString, ok := <-file.channel:
+			if !ok {
+				return
+			}
+			runtime.Gosched()
+			err := logger.baseFile.write(outputString, file.path, logger.output)
+			if err != nil {
+				logger.errorOutput(outputString, err)
+			}
+			atomic.AddInt64(&file.pending, -1)
+		case <-file.quit:
+			return
+		}
+	}
+}
+
+// pending : количество строк в очередях всех файлов и в процессе записи.
+//           number of lines in the queues of all files and being written.
+//
+func (logger *loggerFileMultithreading) pending() int64 {
+	var count int64
+	for _, file := range logger.config {
+		count += atomic.LoadInt64(&file.pending)
+	}
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	for file := range logger.retired {
+		count += atomic.LoadInt64(&file.pending)
+	}
+	return count
+}
+
+// removeFile : удаляет ключ файла. Строки, уже находящиеся в очереди, дописываются
+//              в отдельной горутине, после чего файл закрывается, если он больше не используется.
+//              Вызывается под блокировкой 'Logger', поэтому в этот момент нет горутин-писателей.
+//
+//              removes the file key. Lines already in the queue are written out
+//              in a separate goroutine, after which the file is closed if it's no longer used.
+//              It's called under the 'Logger' lock, so there are no writer goroutines at this moment.
+//
+func (logger *loggerFileMultithreading) removeFile(key string) bool {
+	file, exist := logger.config[key]
+	if !exist {
+		return false
+	}
+	delete(logger.config, key)
+	logger.baseFile.unbind(key)
+	logger.mx.Lock()
+	logger.retired[file] = struct{}{}
+	logger.mx.Unlock()
+	close(file.channel)
+	go func() {
+		<-file.done
+		if file.spill != nil {
+			_ = file.spill.Close()
+		}
+		if err := logger.baseFile.release(file.path); err != nil {
+			out := file.path
+			logger.errorOutput(&out, err)
 		}
+		logger.mx.Lock()
+		delete(logger.retired, file)
+		logger.mx.Unlock()
+	}()
+	return true
+}
+
+// retire : удаляет все ключи файлов, дописывая их очереди.
+//          removes all file keys, writing out their queues.
+//
+func (logger *loggerFileMultithreading) retire() {
+	for key := range logger.config {
+		logger.removeFile(key)
+	}
+}
+
+// drain : удаляет все ключи файлов и дожидается, пока их очереди будут дописаны, а файлы закрыты.
+//         removes all file keys and waits until their queues are written out and the files are closed.
+//
+func (logger *loggerFileMultithreading) drain() {
+	logger.retire()
+	for !logger.idle() {
+		time.Sleep(flushInterval)
+	}
+}
+
+// idle : все удалённые очереди дописаны. | all removed queues are written out.
+//
+func (logger *loggerFileMultithreading) idle() bool {
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	return len(logger.config) == 0 && len(logger.retired) == 0
+}
+
+// stop : останавливает горутины-читатели всех файлов и дожидается их завершения.
+//        stops the reader goroutines of all files and waits for them to exit.
+//
+func (logger *loggerFileMultithreading) stop() {
+	for _, file := range logger.config {
+		close(file.quit)
+	}
+	logger.mx.Lock()
+	retired := make([]*fileAgent, 0, len(logger.retired))
+	for file := range logger.retired {
+		close(file.quit)
+		retired = append(retired, file)
+	}
+	logger.mx.Unlock()
+	for _, file := range logger.config {
+		<-file.done
+		if file.spill != nil {
+			_ = file.spill.Close()
+		}
+	}
+	for _, file := range retired {
+		<-file.done
 	}
 }
 
@@ -94,16 +246,23 @@ func (logger *loggerFileMultithreading) receiver(file fileAgent) {
 // launched for a specific file, in the body of which there is a loop,
 // iterating over the channel, calling the function to write to the file.
 //
-func (logger *loggerFileMultithreading) add(log *logData, param ...string) {
+func (logger *loggerFileMultithreading) add(log *Entry, param ...string) {
 	var (
-		performOutput = func(log *logData, logger *loggerFileMultithreading, key string) {
-			out, err := logger.createOutputString(log)
+		performOutput = func(log *Entry, logger *loggerFileMultithreading, key string) {
+			file, exist := logger.config[key]
+			if exist && log.Lvl < file.level {
+				return
+			}
+			out, err := logger.baseFile.render(log, file)
 			if err != nil {
 				logger.baseFile.errorOutput(out, err)
 				return
 			}
-			if file, exist := logger.config[key]; exist {
-				file.channel <- out
+			if exist {
+				atomic.AddInt64(&file.pending, 1)
+				if !logger.enqueue(file, out) {
+					atomic.AddInt64(&file.pending, -1)
+				}
 				return
 			} else {
 				logger.errorOutput(out, errors.New("loggerFileMultithreading.add isn't exist by key : '"+key+"'"))
@@ -118,12 +277,7 @@ func (logger *loggerFileMultithreading) add(log *logData, param ...string) {
 		}
 		fileKey = key
 	} else {
-		for pckg := range logger.baseFile.config {
-			if strings.Contains(log.Package, pckg) {
-				fileKey = pckg
-				break
-			}
-		}
+		fileKey = log.Route
 	}
 	performOutput(log, logger, fileKey)
 }
@@ -134,57 +288,27 @@ func (logger *loggerFileMultithreading) add(log *logData, param ...string) {
 //
 // Use 'baseFile.createOutputString()'
 //
-func (logger *loggerFileMultithreading) createOutputString(log *logData, param ...string) (*string, error) {
+func (logger *loggerFileMultithreading) createOutputString(log *Entry, param ...string) (*string, error) {
 	return logger.baseFile.createOutputString(log, param...)
 }
 
 // output : implement iLogger interface
 //
-// После открытия файла, создаёт временный буффер,
-// в который будет выполняться запись.
-// Следом, вызывается системный вызов fsync(),
-// для сборса буферов файловой системы на диск.
-// После записи содержимого в буффер,
-// данные сбрасываются в файл через '(*io.Writer).Flush()'.
+// Записывает строку в открытый файл. Так как для каждого файла
+// существует только одна горутина-читатель, запись выполняется
+// одним системным вызовом без дополнительной синхронизации.
 //
-// After opening the file, it creates a temporary buffer to write to.
-// Next, the fsync() system call is called
-// to collect the file system buffers to disk.
-// After writing the content to the buffer,
-// the data is flushed to the file via '(*io.Writer).Flush()'.
+// Writes the line to the open file. Since there is only one
+// reader goroutine for each file, writing is performed
+// with a single system call without additional synchronization.
 //
 func (logger *loggerFileMultithreading) output(out *string, param ...string) error {
-	var (
-		path      = param[0]
-		closeFile = func(file *os.File, err error) error {
-			errFile := file.Close()
-			if errFile != nil {
-				err = errors.New(strings.Join([]string{
-					err.Error(),
-					errFile.Error(),
-				}, "::"))
-			}
-			return err
-		}
-	)
-	file, err := os.OpenFile(path, os.O_APPEND, 0666)
-	if err != nil {
-		return closeFile(file, err)
-	}
-	buffer := bufio.NewWriter(file)
-	err = file.Sync()
-	if err != nil {
-		return closeFile(file, err)
-	}
-	_, err = buffer.WriteString(string(*out) + "\n")
-	if err != nil {
-		return closeFile(file, err)
-	}
-	err = buffer.Flush()
+	handle, err := logger.baseFile.handle(param[0])
 	if err != nil {
-		return closeFile(file, err)
+		return err
 	}
-	return closeFile(file, nil)
+	_, err = handle.WriteString(*out + "\n")
+	return err
 }
 
 // errorOutput : implement iLogger interface
@@ -2,19 +2,19 @@ package gologster
 
 import (
 	"errors"
-	"log"
-	"os"
 	"strings"
 )
 
 // loggerFileMutex : логгер в файл с использованием мьютекса. | logger to file using mutex.
 //
-// Работает со стандартным пакетом 'callingMode', используя 'callingMode.SetOutput(io.Writer)',
-// с переданным в качестве параметра, поток ввода, открытого файла.
+// Каждый файл записывается через собственный открытый файл 'fileHandle',
+// защищённый мьютексом, поэтому строки из разных горутин не перемешиваются,
+// а состояние стандартного пакета 'log' не изменяется.
 // Запись в файл производится в той же гоурутине (потоке), где был вызван 'loggerFileMutex.add()'.
 //
-// Works with the standard package 'callingMode' using 'callingMode.SetOutput(io.Writer)',
-// with an open file input stream passed as a parameter.
+// Each file is written through its own open file 'fileHandle',
+// guarded by a mutex, so lines from different goroutines don't interleave,
+// and the state of the standard package 'log' isn't changed.
 // Writing to the file is done in the same goroutine (stream) where 'loggerFileMutex.add()' was called.
 //
 type loggerFileMutex struct {
@@ -24,43 +24,84 @@ type loggerFileMutex struct {
 
 	// "key" -> fileAgent
 	// EXAMPLE: "sql" -> fileAgent : { path: "./log_sql" }
-	config map[string]fileAgent
+	config map[string]*fileAgent
 }
 
 // newLoggerFileMutex : constructor
 //
-func newLoggerFileMutex(baseFile *loggerBaseFile) *loggerFileMutex {
+func newLoggerFileMutex(baseFile *loggerBaseFile, setup *settings, format *outputFormat) *loggerFileMutex {
 	logger := new(loggerFileMutex)
-	logger.config = make(map[string]fileAgent, 0)
+	logger.config = make(map[string]*fileAgent, 0)
 	logger.baseFile = baseFile
-	for key, path := range baseFile.config {
-		logger.newFile(key, path)
+	for key, path := range baseFile.files() {
+		logger.newFile(key, path, setup, format)
 	}
 	return logger
 }
 
-func (logger *loggerFileMutex) newFile(key, path string) {
-	if _, exist := logger.config[key]; !exist {
-		logger.baseFile.config[key] = path
+// newFile : добавляет ключ файла. Существующий ключ заменяется.
+//           adds a file key. An existing key is replaced.
+//
+func (logger *loggerFileMutex) newFile(key, path string, setup *settings, format *outputFormat) {
+	if _, exist := logger.config[key]; exist {
+		logger.removeFile(key)
 	}
-	file := fileAgent{
-		path: path,
+	logger.baseFile.bind(key, path)
+	logger.baseFile.register(path, setup)
+	file := &fileAgent{
+		path:   path,
+		level:  setup.level,
+		format: format,
 	}
 	logger.config[key] = file
 }
 
+// removeFile : удаляет ключ файла и закрывает файл, если он больше не используется.
+//              Вызывается под блокировкой 'Logger', поэтому запись в файл в этот момент не выполняется.
+//              Файл остаётся открытым, пока дописывается очередь 'loggerFileMultithreading' того же пути.
+//
+//              removes the file key and closes the file if it's no longer used.
+//              It's called under the 'Logger' lock, so no writing to the file is performed at this moment.
+//              The file stays open while the 'loggerFileMultithreading' queue of the same path is written out.
+//
+func (logger *loggerFileMutex) removeFile(key string) bool {
+	file, exist := logger.config[key]
+	if !exist {
+		return false
+	}
+	delete(logger.config, key)
+	logger.baseFile.unbind(key)
+	if err := logger.baseFile.release(file.path); err != nil {
+		out := file.path
+		logger.errorOutput(&out, err)
+	}
+	return true
+}
+
+// retire : удаляет все ключи файлов. | removes all file keys.
+//
+func (logger *loggerFileMutex) retire() {
+	for key := range logger.config {
+		logger.removeFile(key)
+	}
+}
+
 // add : implement iLogger interface
 //
-func (logger *loggerFileMutex) add(log *logData, param ...string) {
+func (logger *loggerFileMutex) add(log *Entry, param ...string) {
 	var (
-		performOutput = func(log *logData, logger *loggerFileMutex, key string) {
-			out, err := logger.createOutputString(log)
+		performOutput = func(log *Entry, logger *loggerFileMutex, key string) {
+			file, exist := logger.config[key]
+			if exist && log.Lvl < file.level {
+				return
+			}
+			out, err := logger.baseFile.render(log, file)
 			if err != nil {
 				logger.baseFile.errorOutput(out, err)
 				return
 			}
-			if file, exist := logger.config[key]; exist {
-				err := logger.output(out, file.path)
+			if exist {
+				err := logger.baseFile.write(out, file.path, logger.output)
 				if err != nil {
 					logger.errorOutput(out, err)
 				}
@@ -78,12 +119,7 @@ func (logger *loggerFileMutex) add(log *logData, param ...string) {
 		}
 		fileKey = key
 	} else {
-		for pckg := range logger.baseFile.config {
-			if strings.Contains(log.Package, pckg) {
-				fileKey = pckg
-				break
-			}
-		}
+		fileKey = log.Route
 	}
 	performOutput(log, logger, fileKey)
 }
@@ -94,34 +130,23 @@ func (logger *loggerFileMutex) add(log *logData, param ...string) {
 //
 // Use 'baseFile.createOutputString()'
 //
-func (logger *loggerFileMutex) createOutputString(log *logData, param ...string) (*string, error) {
+func (logger *loggerFileMutex) createOutputString(log *Entry, param ...string) (*string, error) {
 	return logger.baseFile.createOutputString(log, param...)
 }
 
 // output : implement iLogger interface
 //
 func (logger *loggerFileMutex) output(out *string, param ...string) error {
-	var (
-		path      = param[0]
-		closeFile = func(file *os.File, err error) error {
-			errFile := file.Close()
-			if errFile != nil {
-				err = errors.New(strings.Join([]string{
-					err.Error(),
-					errFile.Error(),
-				}, "::"))
-			}
-			return err
-		}
-	)
-	file, err := os.OpenFile(path, os.O_APPEND, 0666)
+	handle, err := logger.baseFile.handle(param[0])
 	if err != nil {
-		return closeFile(file, nil)
+		return err
+	}
+	line := *out
+	if !strings.HasSuffix(line, "\n") {
+		line += "\n"
 	}
-	log.SetOutput(file)
-	log.SetFlags(log.Flags() &^ (log.Ldate | log.Ltime))
-	log.Print(*out)
-	return closeFile(file, nil)
+	_, err = handle.WriteString(line)
+	return err
 }
 
 // errorOutput : implement iLogger interface
@@ -0,0 +1,187 @@
+package gologster
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+)
+
+// Методы этого файла изменяют маршруты, накопители и файлы уже созданного 'Logger'.
+// Их можно вызывать из любой горутины одновременно с методами логирования:
+// изменения выполняются под 'Logger.mx.Lock()', поэтому каждый лог выводится
+// либо по старой, либо по новой конфигурации.
+//
+// Methods of this file change routes, sinks and files of an already created 'Logger'.
+// They can be called from any goroutine concurrently with the logging methods:
+// changes are performed under 'Logger.mx.Lock()', so each log is output
+// either by the old or by the new configuration.
+
+// AddPackage : добавляет инсталлеры в маршрут пакета 'pckg' (правило, как ключ карты 'Packages(...)').
+//              Если маршрут уже существует, опции добавляются к нему.
+//
+//              adds installers to the route of the package 'pckg' (a rule, like a key of the 'Packages(...)' map).
+//              If the route already exists, options are added to it.
+//
+func (logger *Logger) AddPackage(pckg string, installers ...PackageInstaller) error {
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	if err := logger.live("Logger.AddPackage"); err != nil {
+		return err
+	}
+	return logger.addPackage("Logger.AddPackage", pckg, installers...)
+}
+
+// RemovePackage : удаляет маршрут пакета 'pckg' и файлы, созданные для него инсталлерами пакета.
+//                 removes the route of the package 'pckg' and the files created for it by package installers.
+//
+func (logger *Logger) RemovePackage(pckg string) error {
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	if err := logger.live("Logger.RemovePackage"); err != nil {
+		return err
+	}
+	if !logger.removePackage(pckg) {
+		return errors.New("Logger.RemovePackage : package isn't exist by rule : '" + pckg + "'")
+	}
+	return nil
+}
+
+// ReplacePackage : заменяет маршрут пакета 'pckg' одной операцией.
+//                  replaces the route of the package 'pckg' in one operation.
+//
+func (logger *Logger) ReplacePackage(pckg string, installers ...PackageInstaller) error {
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	if err := logger.live("Logger.ReplacePackage"); err != nil {
+		return err
+	}
+	if _, err := newRule(pckg); err != nil {
+		return errors.New("Logger.ReplacePackage : " + err.Error())
+	}
+	logger.removePackage(pckg)
+	return logger.addPackage("Logger.ReplacePackage", pckg, installers...)
+}
+
+// Install : выполняет инсталлеры режима 'Default' для уже созданного логгера,
+//           например 'DefaultSink(...)' или 'DefaultFileMulti(...)'.
+//           Накопители и ключи файлов с теми же именами заменяются.
+//
+//           executes 'Default' mode installers for an already created logger,
+//           for example 'DefaultSink(...)' or 'DefaultFileMulti(...)'.
+//           Sinks and file keys with the same names are replaced.
+//
+func (logger *Logger) Install(installers ...DefaultInstaller) error {
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	if err := logger.live("Logger.Install"); err != nil {
+		return err
+	}
+	var errs []string
+	for _, mode := range installers {
+		if err := mode(logger); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	return joinErrors("Logger.Install", errs)
+}
+
+// RemoveSink : удаляет пользовательский накопитель 'name' и закрывает его, если он реализует 'io.Closer'.
+//              removes the user sink 'name' and closes it if it implements 'io.Closer'.
+//
+func (logger *Logger) RemoveSink(name string) error {
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	if err := logger.live("Logger.RemoveSink"); err != nil {
+		return err
+	}
+	sink, exist := logger.sinks[name]
+	if !exist {
+		return errors.New("Logger.RemoveSink : sink isn't exist by name : '" + name + "'")
+	}
+	delete(logger.sinks, name)
+	if err := sink.close(); err != nil {
+		return errors.New("Logger.RemoveSink : " + err.Error())
+	}
+	return nil
+}
+
+// RemoveFile : удаляет ключ файла. Строки, уже находящиеся в очереди 'loggerFileMultithreading',
+//              дописываются, после чего файл закрывается.
+//
+//              removes the file key. Lines already in the 'loggerFileMultithreading' queue
+//              are written out, after which the file is closed.
+//
+func (logger *Logger) RemoveFile(key string) error {
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	if err := logger.live("Logger.RemoveFile"); err != nil {
+		return err
+	}
+	if !logger.removeFile(key) {
+		return errors.New("Logger.RemoveFile : file isn't exist by key : '" + key + "'")
+	}
+	return nil
+}
+
+// live : проверяет, что логгер не закрыт. | checks that the logger isn't closed.
+//
+func (logger *Logger) live(method string) error {
+	if atomic.LoadInt32(&logger.closed) == 1 {
+		return errors.New(method + " : logger is closed. ")
+	}
+	return nil
+}
+
+// addPackage : создаёт правило и выполняет инсталлеры пакета. Вызывается под 'mx.Lock()'.
+//              creates the rule and executes the package installers. It's called under 'mx.Lock()'.
+//
+func (logger *Logger) addPackage(method, pckg string, installers ...PackageInstaller) error {
+	if _, err := logger.pckgs.rule(pckg); err != nil {
+		return errors.New(method + " : " + err.Error())
+	}
+	var errs []string
+	for _, mode := range installers {
+		if err := mode(logger, pckg); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	return joinErrors(method, errs)
+}
+
+// removePackage : удаляет маршрут пакета. Вызывается под 'mx.Lock()'.
+//                 removes the package route. It's called under 'mx.Lock()'.
+//
+func (logger *Logger) removePackage(pckg string) bool {
+	if !logger.pckgs.remove(pckg) {
+		return false
+	}
+	if logger.baseConsole != nil {
+		delete(logger.baseConsole.packages, pckg)
+	}
+	for _, sink := range logger.sinks {
+		delete(sink.packages, pckg)
+	}
+	logger.removeFile(pckg)
+	return true
+}
+
+// removeFile : удаляет ключ файла из обоих файловых логгеров. Вызывается под 'mx.Lock()'.
+//              removes the file key from both file loggers. It's called under 'mx.Lock()'.
+//
+func (logger *Logger) removeFile(key string) bool {
+	removed := false
+	if logger.modeFileMutex != nil && logger.modeFileMutex.removeFile(key) {
+		removed = true
+	}
+	if logger.modeFileMulti != nil && logger.modeFileMulti.removeFile(key) {
+		removed = true
+	}
+	return removed
+}
+
+func joinErrors(method string, errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.New(method + " : " + strings.Join(errs, "::"))
+}
@@ -0,0 +1,175 @@
+package gologster
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type discardSink struct{}
+
+func (discardSink) Output(entry *Entry, line string, param ...string) error {
+	return nil
+}
+
+// TestConcurrentReconfiguration : запускать с 'go test -race'.
+//                                 run with 'go test -race'.
+//
+func TestConcurrentReconfiguration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gologster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	const pckg = "github.com/RobertGumpert/logster"
+	var (
+		file = func(name string) string {
+			return filepath.Join(dir, name)
+		}
+		console = PackageConsoleSimple(BaseLogTemplate, MultiThreading).Writers(ioutil.Discard, ioutil.Discard)
+		config  = `{"packages": {"` + pckg + `": [
+			{"type": "file_multi", "file": "` + file("config_multi.txt") + `", "concurrency": "MultiThreading"},
+			{"type": "file_mutex", "file": "` + file("config_mutex.txt") + `"}
+		]}}`
+	)
+	// Ошибки вывода (ключ файла уже удалён) не засоряют вывод теста.
+	// Output errors (the file key is already removed) don't clutter the test output.
+	base := newBase()
+	base.writer = ioutil.Discard
+	logger := newLogger(base)
+	errs := logger.installPackages(map[string][]PackageInstaller{
+		pckg: {
+			console,
+			PackageFileMulti(BaseLogTemplate, MultiThreading, file("multi.txt")),
+			PackageSink("discard", discardSink{}, BaseLogTemplate, MultiThreading),
+		},
+	})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	var (
+		writers sync.WaitGroup
+		stop    = make(chan struct{})
+	)
+	for i := 0; i < 8; i++ {
+		writers.Add(1)
+		go func(i int) {
+			defer writers.Done()
+			for n := 0; ; n++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				logger.Info(n)
+				logger.With("writer", i).Warn(strconv.Itoa(n), GoOptionSink("discard"))
+				logger.Error(n, GoOptionFileMulti(pckg))
+				logger.Info(n, GoOptionFileMutex(pckg), GoOptionConsole())
+				// Даёт выполниться горутинам-читателям, если процессор один.
+				// Lets the reader goroutines run if there is a single processor.
+				time.Sleep(time.Microsecond)
+			}
+		}(i)
+	}
+	for i := 0; i < 20; i++ {
+		switch i % 4 {
+		case 0:
+			_ = logger.AddPackage(pckg, PackageFileMutex(BaseLogTemplate, SingleThreading, file("mutex.txt")))
+		case 1:
+			_ = logger.ReplacePackage(pckg, console, PackageFileMulti(BaseLogTemplate, MultiThreading, file("multi.txt")))
+		case 2:
+			if err := logger.ApplyConfig(strings.NewReader(config)); err != nil {
+				t.Error(err)
+			}
+		case 3:
+			_ = logger.RemovePackage(pckg)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	// Писатели останавливаются до 'Close': иначе при одном процессоре они вытесняют
+	// горутину-читателя и очередь не успевает дописаться до истечения контекста.
+	// The writers are stopped before 'Close': otherwise with a single processor they crowd out
+	// the reader goroutine and the queue isn't written out before the context expires.
+	close(stop)
+	writers.Wait()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := logger.Close(ctx); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRemoveFileWritesQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gologster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var (
+		path  = filepath.Join(dir, "a.txt")
+		files = map[string]string{"a": path}
+	)
+	// Оба файловых логгера используют один ключ и один открытый файл.
+	// Both file loggers use the same key and the same open file.
+	logger := Default(
+		DefaultFileMutex(BaseLogTemplate, files),
+		DefaultFileMulti(BaseLogTemplate, files),
+	)
+	const count = 5000
+	for i := 0; i < count; i++ {
+		logger.Info(i, OptionFileMulti("a"))
+	}
+	if err := logger.RemoveFile("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if lines := countLines(t, path); lines != count {
+		t.Errorf("got %d lines, want %d", lines, count)
+	}
+}
+
+func countLines(t *testing.T, path string) int {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Count(string(data), "\n")
+}
+
+type blockingSink struct {
+	release chan struct{}
+}
+
+func (sink blockingSink) Output(entry *Entry, line string, param ...string) error {
+	<-sink.release
+	return nil
+}
+
+func TestCloseBlockedSink(t *testing.T) {
+	sink := blockingSink{release: make(chan struct{})}
+	defer close(sink.release)
+	logger := Default(DefaultSink("blocking", sink, BaseLogTemplate))
+	logger.Info("blocked", GoOptionSink("blocking"))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- logger.Close(ctx)
+	}()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "1 entries are lost") {
+			t.Errorf("got %v, want lost entry error", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close is blocked after the context is done")
+	}
+}
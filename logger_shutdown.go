@@ -0,0 +1,176 @@
+package gologster
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// flushInterval : интервал проверки очередей при ожидании их опустошения.
+//                 interval for checking the queues while waiting for them to empty.
+//
+const flushInterval = time.Millisecond
+
+// spawn : запускает вывод в отдельной горутине и учитывает её,
+//         чтобы 'Flush' и 'Close' могли дождаться её завершения.
+//
+//         runs the output in a separate goroutine and tracks it,
+//         so that 'Flush' and 'Close' can wait for it to exit.
+//
+func (logger *Logger) spawn(output func()) {
+	atomic.AddInt64(&logger.inflight, 1)
+	go func() {
+		defer atomic.AddInt64(&logger.inflight, -1)
+		logger.mx.RLock()
+		defer logger.mx.RUnlock()
+		output()
+	}()
+}
+
+// pending : количество логов, которые ещё не выведены:
+//           горутины 'GoOption*' и строки в очередях 'loggerFileMultithreading'.
+//
+//           number of logs that haven't been output yet:
+//           'GoOption*' goroutines and lines in the 'loggerFileMultithreading' queues.
+//
+func (logger *Logger) pending() int64 {
+	logger.mx.RLock()
+	defer logger.mx.RUnlock()
+	count := atomic.LoadInt64(&logger.inflight)
+	if logger.modeFileMulti != nil {
+		count += logger.modeFileMulti.pending()
+	}
+	return count
+}
+
+// Flush : дожидается вывода всех логов, находящихся в очередях и в процессе записи.
+//         Если контекст завершится раньше, возвращает ошибку с количеством
+//         ещё не выведенных логов.
+//
+//         waits for the output of all logs that are in the queues and being written.
+//         If the context is done earlier, returns an error with the number
+//         of logs that haven't been output yet.
+//
+func (logger *Logger) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		count := logger.pending()
+		if count == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.New(strings.Join([]string{
+				"Logger.Flush : ",
+				strconv.FormatInt(count, 10),
+				" entries are not written; ",
+				ctx.Err().Error(),
+			}, ""))
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close : дожидается вывода всех логов ('Flush'), останавливает горутины-читатели
+//         'loggerFileMultithreading', закрывает открытые файлы и пользовательские
+//         накопители, реализующие 'io.Closer'. Логи, переданные после вызова 'Close', отбрасываются.
+//         Если контекст завершится раньше, возвращает ошибку с количеством потерянных логов.
+//         Если в этот момент вывод ещё выполняется (например, 'Sink.Output' заблокирован),
+//         файлы и накопители закрываются в фоне после его завершения.
+//
+//         waits for the output of all logs ('Flush'), stops the reader goroutines of
+//         'loggerFileMultithreading', closes open files and user sinks implementing 'io.Closer'.
+//         Logs passed after calling 'Close' are discarded.
+//         If the context is done earlier, returns an error with the number of lost logs.
+//         If output is still running at this moment (for example, 'Sink.Output' is blocked),
+//         files and sinks are closed in the background after it returns.
+//
+func (logger *Logger) Close(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&logger.closed, 0, 1) {
+		return errors.New("Logger.Close : logger is already closed. ")
+	}
+	var (
+		errs []string
+		lost int64
+	)
+	if err := logger.Flush(ctx); err != nil {
+		lost = logger.pending()
+	}
+	// Вывод выполняется под 'mx.RLock()', поэтому блокировка ожидается не дольше контекста.
+	// Output is performed under 'mx.RLock()', so the lock is awaited no longer than the context.
+	locked := make(chan struct{})
+	go func() {
+		logger.mx.Lock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		errs = logger.shutdown()
+		logger.mx.Unlock()
+	case <-ctx.Done():
+		go func() {
+			<-locked
+			defer logger.mx.Unlock()
+			for _, err := range logger.shutdown() {
+				logger.installError(errors.New("Logger.Close : " + err))
+			}
+		}()
+		errs = append(errs, "output is still running, files and sinks are closed after it returns; "+ctx.Err().Error())
+	}
+	if lost != 0 {
+		errs = append(errs, strconv.FormatInt(lost, 10)+" entries are lost; "+ctx.Err().Error())
+	}
+	if len(errs) != 0 {
+		return errors.New("Logger.Close : " + strings.Join(errs, "::"))
+	}
+	return nil
+}
+
+// shutdown : останавливает горутины и закрывает файлы и накопители. Вызывается под 'mx.Lock()'.
+//            stops the goroutines and closes the files and sinks. It's called under 'mx.Lock()'.
+//
+func (logger *Logger) shutdown() []string {
+	var errs []string
+	if logger.watch != nil {
+		close(logger.watch)
+	}
+	if logger.modeFileMulti != nil {
+		logger.modeFileMulti.stop()
+	}
+	for _, baseFile := range logger.baseFiles() {
+		if err := baseFile.close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	for _, sink := range logger.sinks {
+		if err := sink.close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	return errs
+}
+
+// baseFiles : все базовые объекты работы с файлами. В режиме 'Packages'
+//             логгеры 'loggerFileMutex' и 'loggerFileMultithreading' могут иметь разные объекты.
+//
+//             all basic file workers. In 'Packages' mode the loggers
+//             'loggerFileMutex' and 'loggerFileMultithreading' can have different objects.
+//
+func (logger *Logger) baseFiles() []*loggerBaseFile {
+	var files []*loggerBaseFile
+	if logger.baseFile != nil {
+		files = append(files, logger.baseFile)
+	}
+	if logger.modeFileMutex != nil && logger.modeFileMutex.baseFile != logger.baseFile {
+		files = append(files, logger.modeFileMutex.baseFile)
+	}
+	if logger.modeFileMulti != nil && logger.modeFileMulti.baseFile != logger.baseFile &&
+		(logger.modeFileMutex == nil || logger.modeFileMulti.baseFile != logger.modeFileMutex.baseFile) {
+		files = append(files, logger.modeFileMulti.baseFile)
+	}
+	return files
+}
@@ -0,0 +1,138 @@
+package gologster
+
+import (
+	"errors"
+	"io"
+)
+
+// Sink : пользовательский накопитель. | user-defined output.
+//
+// Накопитель получает сам лог и уже готовую строку, созданную по шаблону
+// или кодировщику, указанному в 'DefaultSink' / 'PackageSink'. Маршрутизация по пакетам,
+// фильтрация по уровню, создание строки и вывод ошибок в консоль
+// выполняются так же, как и для встроенных логгеров.
+//
+// The output receives the log itself and a ready-made line created from the template
+// or encoder specified in 'DefaultSink' / 'PackageSink'. Routing by packages,
+// filtering by level, creating the line and error output to the console
+// are performed in the same way as for the built-in loggers.
+//
+type Sink interface {
+	// Output : выполняет вывод лога. | performs log output.
+	//
+	// * entry - лог: значение пользователя, уровень, место вызова, поля и так далее.
+	//           Структурные накопители могут использовать его вместо разбора строки.
+	//           Накопитель не должен изменять лог и хранить его после возврата.
+	//
+	//           log: user value, level, caller, fields and so on.
+	//           Structured sinks can use it instead of parsing the line.
+	//           The sink must not change the log or keep it after returning.
+	//
+	// * line - строка лога.
+	//          logging line.
+	//
+	// * param - параметры, переданные в 'OptionSink' или 'PackageSink'.
+	//           parameters passed to 'OptionSink' or 'PackageSink'.
+	//
+	// Если вернуть ошибку, строка лога будет выведена в консоль вместе с ошибкой.
+	//
+	// If an error is returned, the log line will be output to the console along with the error.
+	//
+	Output(entry *Entry, line string, param ...string) error
+}
+
+// loggerSink : адаптер, связывающий пользовательский 'Sink' с маршрутизацией 'Logger'.
+//              Вместо 'output' интерфейса 'iLogger' вывод выполняется в 'add', так как накопителю передаётся и лог.
+//
+//              adapter that connects user 'Sink' with the 'Logger' routing.
+//              Instead of 'output' of the 'iLogger' interface, output is performed in 'add', since the log is passed to the sink too.
+//
+type loggerSink struct {
+	// Объект базового логгера, со стандартным поведением.
+	// Basic logger object, with standard behavior.
+	base *loggerBase
+	name string
+	sink Sink
+	// Формат логов без маршрута пакета ('OptionSink', 'DefaultSink').
+	// Format of logs without a package route ('OptionSink', 'DefaultSink').
+	format *outputFormat
+	// Пакет -> формат инсталлера 'PackageSink' этого пакета.
+	// Package -> format of the 'PackageSink' installer of this package.
+	packages map[string]*outputFormat
+
+	// Минимальный уровень логов, выводимых в накопитель.
+	// The minimum level of logs output to the sink.
+	level Level
+}
+
+// newLoggerSink : constructor
+//
+func newLoggerSink(base *loggerBase, name string, sink Sink, format *outputFormat) *loggerSink {
+	logger := new(loggerSink)
+	logger.base = base
+	logger.name = name
+	logger.sink = sink
+	logger.format = format
+	logger.packages = make(map[string]*outputFormat)
+	logger.level = LevelTrace
+	return logger
+}
+
+// add : implement iLogger interface
+//
+func (logger *loggerSink) add(log *Entry, param ...string) {
+	if log.Lvl < logger.level {
+		return
+	}
+	out, err := logger.createOutputString(log, param...)
+	if err != nil {
+		logger.errorOutput(out, err)
+		return
+	}
+	err = logger.sink.Output(log, *out, param...)
+	if err != nil {
+		logger.errorOutput(out, err)
+	}
+}
+
+// createOutputString : implement iLogger interface
+//
+func (logger *loggerSink) createOutputString(log *Entry, param ...string) (*string, error) {
+	_ = log.marshal(logger.base)
+	format := logger.format
+	if pckg, exist := logger.packages[log.Route]; exist {
+		format = pckg
+	}
+	return format.render(log)
+}
+
+// errorOutput : implement iLogger interface
+//
+// Поведение определенно базовым логгером  'loggerBase'.
+//
+// The behavior is defined by the base logger 'loggerBase'.
+//
+func (logger *loggerSink) errorOutput(out *string, err error) {
+	logger.base.errorOutput(out, err)
+}
+
+// close : закрывает накопитель, если он реализует 'io.Closer'.
+//         closes the sink if it implements 'io.Closer'.
+//
+func (logger *loggerSink) close() error {
+	if closer, ok := logger.sink.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			return errors.New("sink '" + logger.name + "' : " + err.Error())
+		}
+	}
+	return nil
+}
+
+// missingSink : выводит лог в консоль, если накопитель с таким именем не существует.
+//               outputs the log to the console if the sink with that name doesn't exist.
+//
+func (logger *Logger) missingSink(log *Entry, name string) {
+	_ = log.marshal(logger.base)
+	out := log.filledTemplate(getTextTemplate("base", BaseLogTemplate, BaseLogTemplate))
+	logger.base.errorOutput(out, errors.New("Logger.OptionSink sink isn't exist by name : '"+name+"'"))
+}
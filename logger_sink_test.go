@@ -0,0 +1,35 @@
+package gologster
+
+import (
+	"strings"
+	"testing"
+)
+
+type lineSink struct {
+	lines []string
+}
+
+func (sink *lineSink) Output(entry *Entry, line string, param ...string) error {
+	sink.lines = append(sink.lines, line)
+	return nil
+}
+
+func TestPackageSinkFormat(t *testing.T) {
+	const pckg = "github.com/RobertGumpert/logster"
+	sink := new(lineSink)
+	logger := Packages(map[string][]PackageInstaller{
+		pckg:           {PackageSink("lines", sink, "{{.Value}}", SingleThreading)},
+		"example.com/": {PackageSink("lines", sink, "", SingleThreading).Encoder(JSONEncoder())},
+	})
+	if err := logger.Install(DefaultSink("lines", sink, "option {{.Value}}")); err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("routed")
+	logger.Info("optional", OptionSink("lines"))
+	// JSONEncoder другого пакета и шаблон DefaultSink не меняют формат маршрута пакета.
+	// JSONEncoder of another package and the DefaultSink template don't change the package route format.
+	want := []string{`"routed"`, `option "optional"`}
+	if strings.Join(sink.lines, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", sink.lines, want)
+	}
+}
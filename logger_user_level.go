@@ -1,10 +1,13 @@
 package gologster
 
 import (
+	"context"
 	"errors"
-	"log"
+	"io"
+	"sort"
 	"strings"
-	"text/template"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,13 +20,84 @@ import (
 //                 Created once, for the entire application.
 //
 type Logger struct {
+	// Маршруты, накопители и файлы, общие для логгера и его дочерних логгеров ('WithCallerSkip', 'With').
+	// Routes, outputs and files shared by the logger and its child loggers ('WithCallerSkip', 'With').
+	*loggerCore
+
+	// Количество дополнительно пропускаемых кадров стека при получении информации о вызове.
+	// Number of additionally skipped stack frames when getting caller info.
+	callerSkip int
+
+	// Поля, которые добавляются к каждому логу ('With'). Не изменяются после создания логгера.
+	// Fields that are added to each log ('With'). They aren't changed after the logger is created.
+	fields Fields
+}
+
+// loggerCore : общее состояние логгера. | shared state of the logger.
+//
+type loggerCore struct {
+	// Количество запущенных горутин 'GoOption*' и количество логов,
+	// не подошедших ни под одно правило маршрутизации (используются атомарно).
+	// Number of running 'GoOption*' goroutines and number of logs
+	// that didn't match any routing rule (used atomically).
+	inflight, unrouted int64
+	// Признак закрытия логгера через 'Close' (используется атомарно).
+	// Sign that the logger is closed via 'Close' (used atomically).
+	closed int32
+
+	// Логирование выполняется под 'mx.RLock()', а изменение маршрутов,
+	// накопителей и файлов во время работы - под 'mx.Lock()'.
+	// Logging is performed under 'mx.RLock()', and changing routes,
+	// sinks and files at runtime - under 'mx.Lock()'.
+	mx sync.RWMutex
+
 	base          *loggerBase
 	baseFile      *loggerBaseFile
 	baseConsole   *loggerBaseConsole
 	modeConsole   *loggerConsoleSimple
 	modeFileMulti *loggerFileMultithreading
 	modeFileMutex *loggerFileMutex
-	pckgs         map[string][]Option
+	sinks         map[string]*loggerSink
+	pckgs         *router
+
+	// Сигнал остановки 'Watch'.
+	// The 'Watch' stop signal.
+	watch chan struct{}
+
+	// Минимальный уровень среди всех накопителей и маршрутов.
+	// The minimum level among all outputs and routes.
+	level Level
+
+	// Поток для вывода информации о логах без маршрута ('TraceUnrouted').
+	// Stream for outputting information about logs without a route ('TraceUnrouted').
+	unroutedTrace atomic.Value
+
+	// Захват стека вызовов ('CaptureStack'), nil - отключён.
+	// Call stack capture ('CaptureStack'), nil - disabled.
+	stack *StackCapture
+
+	// Извлекатели полей из контекста методов '*Ctx' ('DefaultContext'), общие для всех пакетов.
+	// Извлекатели 'PackageContext' хранятся в правилах маршрутизации.
+	// Extractors of fields from the context of the '*Ctx' methods ('DefaultContext'), shared by all packages.
+	// 'PackageContext' extractors are stored in the routing rules.
+	extractors []ContextExtractor
+
+	// Настройки инсталлера, который выполняется в данный момент.
+	// Settings of the installer that is currently running.
+	setup []func(s *settings)
+}
+
+// route : опция, через которую выводятся логи пакета, и её минимальный уровень.
+//         option through which the package logs are output, and its minimum level.
+//
+type route struct {
+	option Option
+	level  Level
+
+	// Описание накопителя для 'AdminHandler': "console", "file_mutex", "file_multi", "sink:<name>".
+	// Output description for 'AdminHandler': "console", "file_mutex", "file_multi", "sink:<name>".
+	output        string
+	isConcurrency concurrency
 }
 
 type DefaultInstaller func(logger *Logger) error
@@ -39,32 +113,54 @@ const MultiThreading concurrency = true
 
 func DefaultConsoleSimple(templateString string, params ...map[string]string) DefaultInstaller {
 	return func(logger *Logger) error {
-		packages := make(map[string]struct{})
-		tmpl, err := template.New("console_simple").Parse(templateString)
+		packages := make(map[string]*consoleFormat)
+		tmpl, err := logger.textTemplate("console_simple", templateString, BaseLogTemplate)
 		if err != nil {
-			tmpl, _ = template.New("console_simple").Parse(BaseLogTemplate)
+			return errors.New("DefaultConsoleSimple : " + err.Error())
 		}
-		logger.baseConsole = newBaseConsole(logger.base, packages, tmpl)
+		setup := logger.settings()
+		logger.baseConsole = newBaseConsole(logger.base, packages, newConsoleFormat(logger.base, newOutputFormat(tmpl, setup), setup))
 		logger.modeConsole = newLoggerConsoleSimple(logger.baseConsole)
+		logger.modeConsole.level = setup.level
+		logger.threshold(logger.modeConsole.level)
 		return nil
 	}
 }
 
+// DefaultConsolePretty : консоль с кодировщиком 'PrettyEncoder' для локальной разработки.
+//                        console with the 'PrettyEncoder' encoder for local development.
+//
+func DefaultConsolePretty(params ...map[string]string) DefaultInstaller {
+	return DefaultConsoleSimple(BaseLogTemplate, params...).Encoder(PrettyEncoder())
+}
+
 func DefaultFileMutex(templateString string, params ...map[string]string) DefaultInstaller {
 	return func(logger *Logger) error {
 		if logger.baseFile == nil {
-			if len(params) == 0 && len(params[0]) == 0 {
+			if len(params) == 0 || len(params[0]) == 0 {
 				return errors.New("DefaultFileMutex : File map isn't exist. ")
 			}
 		}
-		tmpl, err := template.New("file_mutex").Parse(templateString)
+		tmpl, err := logger.textTemplate("file_mutex", templateString, BaseLogTemplate)
 		if err != nil {
-			tmpl, _ = template.New("file_mutex").Parse(BaseLogTemplate)
+			return errors.New("DefaultFileMutex : " + err.Error())
 		}
+		setup := logger.settings()
+		format := newOutputFormat(tmpl, setup)
 		if logger.baseFile == nil {
-			logger.baseFile = newBaseFile(logger.base, params[0], tmpl)
+			logger.baseFile = newBaseFile(logger.base, make(map[string]string), format)
 		}
-		logger.modeFileMutex = newLoggerFileMutex(logger.baseFile)
+		if logger.modeFileMutex == nil {
+			for key, path := range logger.defaultFiles(params...) {
+				logger.baseFile.bind(key, path)
+			}
+			logger.modeFileMutex = newLoggerFileMutex(logger.baseFile, setup, format)
+		} else {
+			for key, path := range logger.defaultFiles(params...) {
+				logger.modeFileMutex.newFile(key, path, setup, format)
+			}
+		}
+		logger.threshold(setup.level)
 		return nil
 	}
 }
@@ -72,76 +168,106 @@ func DefaultFileMutex(templateString string, params ...map[string]string) Defaul
 func DefaultFileMulti(templateString string, params ...map[string]string) DefaultInstaller {
 	return func(logger *Logger) error {
 		if logger.baseFile == nil {
-			if len(params) == 0 && len(params[0]) == 0 {
+			if len(params) == 0 || len(params[0]) == 0 {
 				return errors.New("DefaultFileMulti : File map isn't exist. ")
 			}
 		}
-		tmpl, err := template.New("file_mutex").Parse(templateString)
+		tmpl, err := logger.textTemplate("file_multi", templateString, BaseLogTemplate)
 		if err != nil {
-			tmpl, _ = template.New("file_mutex").Parse(BaseLogTemplate)
+			return errors.New("DefaultFileMulti : " + err.Error())
 		}
+		setup := logger.settings()
+		format := newOutputFormat(tmpl, setup)
 		if logger.baseFile == nil {
-			logger.baseFile = newBaseFile(logger.base, params[0], tmpl)
+			logger.baseFile = newBaseFile(logger.base, make(map[string]string), format)
+		}
+		if logger.modeFileMulti == nil {
+			for key, path := range logger.defaultFiles(params...) {
+				logger.baseFile.bind(key, path)
+			}
+			logger.modeFileMulti = newLoggerFileMultithreading(logger.baseFile, setup, format)
+		} else {
+			for key, path := range logger.defaultFiles(params...) {
+				logger.modeFileMulti.newFile(key, path, setup, format)
+			}
 		}
-		logger.modeFileMulti = newLoggerFileMultithreading(logger.baseFile)
+		logger.threshold(setup.level)
 		return nil
 	}
 }
 
+// defaultFiles : объединяет карты файлов, переданные в 'DefaultFileMutex' / 'DefaultFileMulti'.
+//                merges file maps passed to 'DefaultFileMutex' / 'DefaultFileMulti'.
+//
+func (logger *Logger) defaultFiles(params ...map[string]string) map[string]string {
+	files := make(map[string]string)
+	for _, param := range params {
+		for key, path := range param {
+			files[key] = path
+		}
+	}
+	return files
+}
+
 func PackageConsoleSimple(templateString string, isConcurrency concurrency, params ...string) PackageInstaller {
 	return func(logger *Logger, pckg string) error {
-		tmpl, err := template.New("console_simple").Parse(templateString)
+		tmpl, err := logger.textTemplate("console_simple", templateString, BaseLogTemplate)
 		if err != nil {
-			tmpl, _ = template.New("console_simple").Parse(BaseLogTemplate)
+			return errors.New("PackageConsoleSimple : " + err.Error())
 		}
 		//
+		setup := logger.settings()
+		format := newConsoleFormat(logger.base, newOutputFormat(tmpl, setup), setup)
 		if logger.modeConsole == nil {
-			packages := make(map[string]struct{})
-			packages[pckg] = struct{}{}
-			logger.baseConsole = newBaseConsole(logger.base, packages, tmpl)
+			packages := make(map[string]*consoleFormat)
+			packages[pckg] = format
+			logger.baseConsole = newBaseConsole(logger.base, packages, format)
 			logger.modeConsole = newLoggerConsoleSimple(logger.baseConsole)
 		} else {
-			logger.baseConsole.packages[pckg] = struct{}{}
+			logger.baseConsole.packages[pckg] = format
 		}
 		//
 		if isConcurrency {
-			logger.pckgs[pckg] = append(logger.pckgs[pckg], GoOptionConsole)
-		} else {
-			logger.pckgs[pckg] = append(logger.pckgs[pckg], OptionConsole)
+			return logger.addRoute(pckg, "console", MultiThreading, GoOptionConsole)
 		}
-		//
-		return nil
+		return logger.addRoute(pckg, "console", SingleThreading, OptionConsole)
 	}
 }
 
+// PackageConsolePretty : консоль пакета с кодировщиком 'PrettyEncoder' для локальной разработки.
+//                        package console with the 'PrettyEncoder' encoder for local development.
+//
+func PackageConsolePretty(isConcurrency concurrency, params ...string) PackageInstaller {
+	return PackageConsoleSimple(BaseLogTemplate, isConcurrency, params...).Encoder(PrettyEncoder())
+}
+
 func PackageFileMutex(templateString string, isConcurrency concurrency, params ...string) PackageInstaller {
 	return func(logger *Logger, pckg string) error {
 		//
-		tmpl, err := template.New("console_simple").Parse(templateString)
+		tmpl, err := logger.textTemplate("file_mutex", templateString, BaseLogTemplate)
 		if err != nil {
-			tmpl, _ = template.New("console_simple").Parse(BaseLogTemplate)
+			return errors.New("PackageFileMutex : " + err.Error())
 		}
 		//
+		setup := logger.settings()
+		format := newOutputFormat(tmpl, setup)
 		if logger.modeFileMutex == nil {
 			packages := make(map[string]string, 0)
 			for _, file := range params {
 				packages[pckg] = file
 			}
-			logger.baseFile = newBaseFile(logger.base, packages, tmpl)
-			logger.modeFileMutex = newLoggerFileMutex(logger.baseFile)
+			logger.baseFile = newBaseFile(logger.base, packages, format)
+			logger.modeFileMutex = newLoggerFileMutex(logger.baseFile, setup, format)
 		} else {
 			for _, file := range params {
-				logger.modeFileMutex.newFile(pckg, file)
+				logger.modeFileMutex.newFile(pckg, file, setup, format)
 			}
 		}
 		//
 		if isConcurrency {
-			logger.pckgs[pckg] = append(logger.pckgs[pckg], GoOptionFileMutex)
-		} else {
-			logger.pckgs[pckg] = append(logger.pckgs[pckg], OptionFileMutex)
+			return logger.addRoute(pckg, "file_mutex", MultiThreading, GoOptionFileMutex)
 		}
-		//
-		return nil
+		return logger.addRoute(pckg, "file_mutex", SingleThreading, OptionFileMutex)
 	}
 }
 
@@ -149,136 +275,375 @@ func PackageFileMutex(templateString string, isConcurrency concurrency, params .
 func PackageFileMulti(templateString string, isConcurrency concurrency, params ...string) PackageInstaller {
 	return func(logger *Logger, pckg string) error {
 		//
-		tmpl, err := template.New("console_simple").Parse(templateString)
+		tmpl, err := logger.textTemplate("file_multi", templateString, BaseLogTemplate)
 		if err != nil {
-			tmpl, _ = template.New("console_simple").Parse(BaseLogTemplate)
+			return errors.New("PackageFileMulti : " + err.Error())
 		}
 		//
+		setup := logger.settings()
+		format := newOutputFormat(tmpl, setup)
 		if logger.modeFileMulti == nil {
 			packages := make(map[string]string, 0)
 			for _, file := range params {
 				packages[pckg] = file
 			}
-			logger.baseFile = newBaseFile(logger.base, packages, tmpl)
-			logger.modeFileMulti = newLoggerFileMultithreading(logger.baseFile)
+			logger.baseFile = newBaseFile(logger.base, packages, format)
+			logger.modeFileMulti = newLoggerFileMultithreading(logger.baseFile, setup, format)
 		} else {
 			for _, file := range params {
-				logger.modeFileMulti.newFile(pckg, file)
+				logger.modeFileMulti.newFile(pckg, file, setup, format)
 			}
 		}
 		//
 		if isConcurrency {
-			logger.pckgs[pckg] = append(logger.pckgs[pckg], GoOptionFileMutex)
-		} else {
-			logger.pckgs[pckg] = append(logger.pckgs[pckg], OptionFileMutex)
+			return logger.addRoute(pckg, "file_multi", MultiThreading, GoOptionFileMulti)
 		}
-		//
+		return logger.addRoute(pckg, "file_multi", SingleThreading, OptionFileMulti)
+	}
+}
+
+
+// DefaultSink : регистрирует пользовательский накопитель под именем 'name'.
+//               Вывод в него выполняется через 'OptionSink(name)'.
+//
+//               registers a user sink under the name 'name'.
+//               Output to it is performed via 'OptionSink(name)'.
+//
+func DefaultSink(name string, sink Sink, templateString string) DefaultInstaller {
+	return func(logger *Logger) error {
+		if sink == nil {
+			return errors.New("DefaultSink : Sink '" + name + "' is nil. ")
+		}
+		tmpl, err := logger.textTemplate("sink_"+name, templateString, BaseLogTemplate)
+		if err != nil {
+			return errors.New("DefaultSink : " + err.Error())
+		}
+		setup := logger.settings()
+		registered := newLoggerSink(logger.base, name, sink, newOutputFormat(tmpl, setup))
+		registered.level = setup.level
+		if replaced, exist := logger.sinks[name]; exist {
+			registered.packages = replaced.packages
+			if replaced.sink != sink {
+				if err := replaced.close(); err != nil {
+					logger.installError(err)
+				}
+			}
+		}
+		logger.sinks[name] = registered
+		logger.threshold(registered.level)
 		return nil
 	}
 }
 
+// PackageSink : регистрирует пользовательский накопитель под именем 'name'
+//               (если он ещё не зарегистрирован) и добавляет его в маршрут пакета.
+//               Параметры 'params' передаются в 'Sink.Output'. Шаблон, кодировщик и формат времени
+//               применяются только к логам этого пакета.
+//
+//               registers a user sink under the name 'name'
+//               (if it isn't registered yet) and adds it to the package route.
+//               Parameters 'params' are passed to 'Sink.Output'. The template, encoder and time format
+//               are applied only to the logs of this package.
+//
+func PackageSink(name string, sink Sink, templateString string, isConcurrency concurrency, params ...string) PackageInstaller {
+	return func(logger *Logger, pckg string) error {
+		registered, exist := logger.sinks[name]
+		if !exist && sink == nil {
+			return errors.New("PackageSink : Sink '" + name + "' is nil. ")
+		}
+		tmpl, err := logger.textTemplate("sink_"+name, templateString, BaseLogTemplate)
+		if err != nil {
+			return errors.New("PackageSink : " + err.Error())
+		}
+		format := newOutputFormat(tmpl, logger.settings())
+		if !exist {
+			registered = newLoggerSink(logger.base, name, sink, format)
+			logger.sinks[name] = registered
+		}
+		registered.packages[pckg] = format
+		option := OptionSink
+		if isConcurrency {
+			option = GoOptionSink
+		}
+		return logger.addRoute(pckg, "sink:"+name, isConcurrency, func(param ...string) Mode {
+			return option(name, params...)
+		})
+	}
+}
 
 // Default : создаёт базовый пользовательский интерфейс, с выводом в консоль.
 //           filledTemplate a base user interface, with output to the console.
 //
 func Default(installers ...DefaultInstaller) *Logger {
-	logger := new(Logger)
-	logger.base = newBase()
+	logger := newLogger(newBase())
+	for _, err := range logger.installDefault(installers...) {
+		logger.installError(err)
+	}
+	logger.applyEnv()
+	return logger
+}
+
+func Packages(packages map[string][]PackageInstaller) *Logger {
+	logger := newLogger(newBase())
+	for _, err := range logger.installPackages(packages) {
+		logger.installError(err)
+	}
+	logger.applyEnv()
+	return logger
+}
+
+// newLogger : constructor
+//
+func newLogger(base *loggerBase) *Logger {
+	logger := &Logger{loggerCore: new(loggerCore)}
+	logger.base = base
+	logger.level = LevelFatal
+	logger.sinks = make(map[string]*loggerSink)
+	logger.pckgs = newRouter()
+	return logger
+}
+
+// installDefault : выполняет инсталлеры режима 'Default' и возвращает их ошибки.
+//                  executes 'Default' mode installers and returns their errors.
+//
+func (logger *Logger) installDefault(installers ...DefaultInstaller) []error {
+	var errs []error
 	for _, mode := range installers {
 		err := mode(logger)
 		if err != nil {
-			log.Println(err)
+			errs = append(errs, err)
 		}
 	}
-	return logger
+	// Логи без явных опций выводятся в консоль, если она установлена.
+	// Logs without explicit options are output to the console, if it's installed.
+	if _, exist := logger.pckgs.keys[RouteFallback]; !exist && logger.modeConsole != nil {
+		_ = logger.pckgs.add(RouteFallback, route{
+			option: OptionConsole,
+			output: "console",
+			level:  logger.modeConsole.level,
+		})
+	}
+	return errs
 }
 
-func Packages(packages map[string][]PackageInstaller) *Logger {
-	logger := new(Logger)
-	logger.base = newBase()
-	logger.pckgs = make(map[string][]Option, 0)
-	for name, installers := range packages {
-		for _ , mode := range installers {
+// installPackages : выполняет инсталлеры режима 'Packages' и возвращает их ошибки.
+//                   executes 'Packages' mode installers and returns their errors.
+//
+func (logger *Logger) installPackages(packages map[string][]PackageInstaller) []error {
+	var errs []error
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	// Инсталлеры выполняются в одном и том же порядке при каждом запуске.
+	// Installers are executed in the same order on every run.
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := logger.pckgs.rule(name); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, mode := range packages[name] {
 			err := mode(logger, name)
 			if err != nil {
-				log.Println(err)
+				errs = append(errs, err)
 			}
 		}
 	}
-	return logger
+	return errs
+}
+
+// installError : выводит ошибку инсталлера в консоль.
+//                outputs the installer error to the console.
+//
+func (logger *Logger) installError(err error) {
+	out := err.Error()
+	_ = logger.base.output(&out)
+}
+
+// Trace : логирование уровня 'trace'.
+//         logging level 'trace'.
+//
+func (logger *Logger) Trace(value interface{}, modes ...Mode) {
+	logger.logging(nil, value, LevelTrace, modes...)
+}
+
+// Debug : логирование уровня 'debug'.
+//         logging level 'debug'.
+//
+func (logger *Logger) Debug(value interface{}, modes ...Mode) {
+	logger.logging(nil, value, LevelDebug, modes...)
 }
 
 // Info : логирование уровня 'info'.
 //        logging level 'info'.
 //
 func (logger *Logger) Info(value interface{}, modes ...Mode) {
-	date := time.Now().Format("Mon Jan _2 15:04:05 2006")
-	data := newLogData(value, levelInfo, date).setRuntimeInfo(3)
-	_ = data.marshal(logger.base)
-	if len(modes) != 0 {
-		data.IsOption = true
-		logger.callingMode(data, modes...)
-	} else {
-		for pckg, options := range logger.pckgs {
-			if strings.Contains(data.Package, pckg) {
-				data.Package = pckg
-				logger.callingOption(data, options...)
-				break
-			}
-		}
-	}
+	logger.logging(nil, value, LevelInfo, modes...)
+}
+
+// Warn : логирование уровня 'warn'.
+//        logging level 'warn'.
+//
+func (logger *Logger) Warn(value interface{}, modes ...Mode) {
+	logger.logging(nil, value, LevelWarn, modes...)
 }
 
 // Error : логирование уровня 'error'.
 //         logging level 'error'.
 //
 func (logger *Logger) Error(value interface{}, modes ...Mode) {
-	date := time.Now().Format("Mon Jan _2 15:04:05 2006")
-	data := newLogData(value, levelError, date).setRuntimeInfo(4)
-	_ = data.marshal(logger.base)
-	if len(modes) != 0 {
-		data.IsOption = true
-		logger.callingMode(data, modes...)
-	} else {
-		for pckg, options := range logger.pckgs {
-			if strings.Contains(data.Package, pckg) {
-				data.Package = pckg
-				logger.callingOption(data, options...)
-				break
-			}
-		}
-	}
+	logger.logging(nil, value, LevelError, modes...)
 }
 
 // Panic : логирование уровня 'panic'.
+//         Как и прежде, только записывает лог и не вызывает 'panic()'.
+//
 //         logging level 'panic'.
+//         As before, it only writes the log and doesn't call 'panic()'.
 //
 func (logger *Logger) Panic(value interface{}, modes ...Mode) {
-	date := time.Now().Format("Mon Jan _2 15:04:05 2006")
-	data := newLogData(value, levelPanic, date).setRuntimeInfo(4)
-	_ = data.marshal(logger.base)
+	logger.logging(nil, value, LevelPanic, modes...)
+}
+
+// Fatal : логирование уровня 'fatal'.
+//         Как и 'Panic', только записывает лог и не завершает процесс.
+//
+//         logging level 'fatal'.
+//         Like 'Panic', it only writes the log and doesn't exit the process.
+//
+func (logger *Logger) Fatal(value interface{}, modes ...Mode) {
+	logger.logging(nil, value, LevelFatal, modes...)
+}
+
+// logging : общая реализация для всех уровней логирования.
+//           Вызывается только из методов уровней, поэтому
+//           глубина стека до пользовательского кода всегда одинакова.
+//
+//           common implementation for all logging levels.
+//           It is called only from level methods, so the stack depth
+//           to the user code is always the same.
+//
+func (logger *Logger) logging(ctx context.Context, value interface{}, lvl Level, modes ...Mode) {
+	logger.mx.RLock()
+	defer logger.mx.RUnlock()
+	if lvl < logger.level || atomic.LoadInt32(&logger.closed) == 1 {
+		return
+	}
+	data := newEntry(value, lvl, time.Now()).setRuntimeInfo(4 + logger.callerSkip)
+	var matched *rule
+	if len(modes) == 0 || ctx != nil {
+		matched = logger.pckgs.match(data.Package)
+	}
+	data.Fields = logger.contextFields(ctx, matched)
+	if logger.stack != nil && lvl >= logger.stack.Level {
+		data.Stack = captureStack(4+logger.callerSkip, logger.stack)
+	}
 	if len(modes) != 0 {
 		data.IsOption = true
 		logger.callingMode(data, modes...)
 	} else {
-		for pckg, options := range logger.pckgs {
-			if strings.Contains(data.Package, pckg) {
-				data.Package = pckg
-				logger.callingOption(data, options...)
-				break
+		if matched != nil {
+			if matched.override != nil && matched.override.muted {
+				return
 			}
+			data.Route = matched.key
+			logger.callingRoute(data, matched.routes...)
+		} else {
+			logger.unroutedEntry(data)
 		}
 	}
 }
 
-func (logger *Logger) callingOption(log *logData, options ...Option) {
-	for _, option := range options {
-		mode := option()
+// WithCallerSkip : дочерний логгер, который пропускает 'skip' дополнительных кадров стека
+//                  при получении информации о вызове. Используется библиотеками-обёртками над 'Logger',
+//                  чтобы в логах указывалось место вызова обёртки. Дочерний логгер использует
+//                  маршруты, накопители и файлы родителя, 'Close' любого из них закрывает оба.
+//
+//                  child logger that skips 'skip' additional stack frames
+//                  when getting caller info. It's used by wrapper libraries around 'Logger'
+//                  so that logs point to the place where the wrapper is called. The child logger uses
+//                  the routes, outputs and files of the parent, 'Close' of either of them closes both.
+//
+// EXAMPLE: func (w *Wrapper) Info(msg string) { w.logger.Info(msg) }, where w.logger = logger.WithCallerSkip(1)
+//
+func (logger *Logger) WithCallerSkip(skip int) *Logger {
+	return &Logger{
+		loggerCore: logger.loggerCore,
+		callerSkip: logger.callerSkip + skip,
+		fields:     logger.fields,
+	}
+}
+
+// unroutedEntry : учитывает лог, не подошедший ни под одно правило маршрутизации.
+//                 counts the log that didn't match any routing rule.
+//
+func (logger *Logger) unroutedEntry(log *Entry) {
+	atomic.AddInt64(&logger.unrouted, 1)
+	if trace, ok := logger.unroutedTrace.Load().(unroutedWriter); ok && trace.writer != nil {
+		_, _ = io.WriteString(trace.writer, strings.Join([]string{
+			"gologster: unrouted entry;",
+			"level=[" + log.Level + "];",
+			"package=[" + log.Package + "];",
+			"func=[" + log.Func + "];",
+			"line=[" + log.Line + "];\n",
+		}, ""))
+	}
+}
+
+// unroutedWriter : обёртка для хранения 'io.Writer' в 'atomic.Value', в том числе 'nil'.
+//                  wrapper for storing 'io.Writer' in 'atomic.Value', including 'nil'.
+//
+type unroutedWriter struct {
+	writer io.Writer
+}
+
+// Unrouted : количество логов, не подошедших ни под одно правило маршрутизации.
+//            number of logs that didn't match any routing rule.
+//
+func (logger *Logger) Unrouted() int64 {
+	return atomic.LoadInt64(&logger.unrouted)
+}
+
+// TraceUnrouted : выводит в 'writer' информацию о каждом логе без маршрута.
+//                 'nil' отключает вывод.
+//
+//                 outputs information about each log without a route to 'writer'.
+//                 'nil' disables output.
+//
+func (logger *Logger) TraceUnrouted(writer io.Writer) {
+	logger.unroutedTrace.Store(unroutedWriter{writer})
+}
+
+// addRoute : добавляет опцию в маршрут пакета с уровнем текущего инсталлера.
+//            adds an option to the package route with the level of the current installer.
+//
+func (logger *Logger) addRoute(pckg, output string, isConcurrency concurrency, option Option) error {
+	lvl := logger.settings().level
+	err := logger.pckgs.add(pckg, route{
+		option:        option,
+		level:         lvl,
+		output:        output,
+		isConcurrency: isConcurrency,
+	})
+	if err != nil {
+		return err
+	}
+	logger.threshold(lvl)
+	return nil
+}
+
+func (logger *Logger) callingRoute(log *Entry, routes ...route) {
+	for _, route := range routes {
+		if log.Lvl < route.level {
+			continue
+		}
+		mode := route.option()
 		mode(logger, log)
 	}
 }
 
-func (logger *Logger) callingMode(log *logData, modes ...Mode) {
+func (logger *Logger) callingMode(log *Entry, modes ...Mode) {
 	for _, mode := range modes {
 		mode(logger, log)
 	}
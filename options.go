@@ -21,7 +21,7 @@ package gologster
 // Mode : в теле содержит вызов метода 'add' конкретного логгера.
 //        in the body contains a call to the 'add' method of a particular logger.
 //
-type Mode func(logger *Logger, log *logData)
+type Mode func(logger *Logger, log *Entry)
 
 // IsOption : возвращает 'Mode' соответствующий  выбранной пользователем опции.
 //          returns 'Mode' corresponding to the option selected by the user.
@@ -34,8 +34,8 @@ type Option func(param ...string) Mode
 //           Call on the same thread.
 //
 func OptionConsole(param ...string) Mode {
-	return func(logger *Logger, log *logData) {
-		logger.modeConsole.add(log, param...)
+	return func(logger *Logger, log *Entry) {
+		logger.console(log, param...)
 	}
 }
 
@@ -45,11 +45,8 @@ func OptionConsole(param ...string) Mode {
 //             Call on the same thread.
 //
 func OptionFileMulti(param ...string) Mode {
-	return func(logger *Logger, log *logData) {
-		if logger.modeFileMulti == nil {
-			logger.modeConsole.add(log, param...)
-		}
-		logger.modeFileMulti.add(log, param...)
+	return func(logger *Logger, log *Entry) {
+		logger.fileMulti(log, param...)
 	}
 }
 
@@ -59,11 +56,8 @@ func OptionFileMulti(param ...string) Mode {
 //             Call on the same thread.
 //
 func OptionFileMutex(param ...string) Mode {
-	return func(logger *Logger, log *logData) {
-		if logger.modeFileMutex == nil {
-			logger.modeConsole.add(log, param...)
-		}
-		logger.modeFileMutex.add(log, param...)
+	return func(logger *Logger, log *Entry) {
+		logger.fileMutex(log, param...)
 	}
 }
 
@@ -73,8 +67,10 @@ func OptionFileMutex(param ...string) Mode {
 //             Call in a separate thread.
 //
 func GoOptionConsole(param ...string) Mode {
-	return func(logger *Logger, log *logData) {
-		go logger.modeConsole.add(log, param...)
+	return func(logger *Logger, log *Entry) {
+		logger.spawn(func() {
+			logger.console(log, param...)
+		})
 	}
 }
 
@@ -84,11 +80,10 @@ func GoOptionConsole(param ...string) Mode {
 //               Call in a separate thread.
 //
 func GoOptionFileMulti(param ...string) Mode {
-	return func(logger *Logger, log *logData) {
-		if logger.modeFileMulti == nil {
-			go logger.modeConsole.add(log, param...)
-		}
-		go logger.modeFileMulti.add(log, param...)
+	return func(logger *Logger, log *Entry) {
+		logger.spawn(func() {
+			logger.fileMulti(log, param...)
+		})
 	}
 }
 
@@ -98,10 +93,85 @@ func GoOptionFileMulti(param ...string) Mode {
 //               Call in a separate thread.
 //
 func GoOptionFileMutex(param ...string) Mode {
-	return func(logger *Logger, log *logData) {
-		if logger.modeFileMutex == nil {
-			go logger.modeConsole.add(log, param...)
-		}
-		go logger.modeFileMutex.add(log, param...)
+	return func(logger *Logger, log *Entry) {
+		logger.spawn(func() {
+			logger.fileMutex(log, param...)
+		})
 	}
 }
+
+// OptionSink : возвращает 'Mode' соответствующий пользовательскому накопителю 'name'.
+//              Вызов в том же потоке.
+//              returns 'Mode' corresponding to the user sink 'name'.
+//              Call on the same thread.
+//
+func OptionSink(name string, param ...string) Mode {
+	return func(logger *Logger, log *Entry) {
+		logger.sink(log, name, param...)
+	}
+}
+
+// GoOptionSink : возвращает 'Mode' соответствующий пользовательскому накопителю 'name'.
+//                Вызов в отдельном потоке.
+//                returns 'Mode' corresponding to the user sink 'name'.
+//                Call in a separate thread.
+//
+func GoOptionSink(name string, param ...string) Mode {
+	return func(logger *Logger, log *Entry) {
+		logger.spawn(func() {
+			logger.sink(log, name, param...)
+		})
+	}
+}
+
+// Вывод выбирается в момент записи, а не в момент вызова опции: горутина 'GoOption*'
+// может выполниться уже после 'ApplyConfig', 'RemovePackage' или 'RemoveSink'.
+//
+// The output is chosen at the moment of writing, not at the moment the option is called:
+// a 'GoOption*' goroutine can run after 'ApplyConfig', 'RemovePackage' or 'RemoveSink'.
+
+// console : выводит лог в консоль или, если консоль не установлена, в поток базового логгера.
+//           outputs the log to the console or, if the console isn't installed, to the base logger stream.
+//
+func (logger *Logger) console(log *Entry, param ...string) {
+	if logger.modeConsole == nil {
+		_ = log.marshal(logger.base)
+		out := log.filledTemplate(getTextTemplate("base", BaseLogTemplate, BaseLogTemplate))
+		_ = logger.base.output(out)
+		return
+	}
+	logger.modeConsole.add(log, param...)
+}
+
+// fileMulti : выводит лог в 'loggerFileMultithreading' или в консоль, если он не установлен.
+//             outputs the log to 'loggerFileMultithreading' or to the console if it isn't installed.
+//
+func (logger *Logger) fileMulti(log *Entry, param ...string) {
+	if logger.modeFileMulti == nil {
+		logger.console(log, param...)
+		return
+	}
+	logger.modeFileMulti.add(log, param...)
+}
+
+// fileMutex : выводит лог в 'loggerFileMutex' или в консоль, если он не установлен.
+//             outputs the log to 'loggerFileMutex' or to the console if it isn't installed.
+//
+func (logger *Logger) fileMutex(log *Entry, param ...string) {
+	if logger.modeFileMutex == nil {
+		logger.console(log, param...)
+		return
+	}
+	logger.modeFileMutex.add(log, param...)
+}
+
+// sink : выводит лог в накопитель 'name'. | outputs the log to the sink 'name'.
+//
+func (logger *Logger) sink(log *Entry, name string, param ...string) {
+	sink, exist := logger.sinks[name]
+	if !exist {
+		logger.missingSink(log, name)
+		return
+	}
+	sink.add(log, param...)
+}
@@ -2,26 +2,84 @@ package gologster
 
 import (
 	"bytes"
+	"errors"
+	"io"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 )
 
 const (
 	BaseLogTemplate string = "level=[{{.Level}}];func=[name: {{.Func}}, line: {{.Line}}, package:{{.Package}}];value=[{{.Value}}];date=[{{.Date}}];"
 )
 
-// level : уровень логирования | logging level
+// dateLayout : формат 'Entry.Date'. | 'Entry.Date' format.
 //
-type level int
+const dateLayout = "Mon Jan _2 15:04:05 2006"
+
+// Level : уровень логирования | logging level
+//
+// Уровни упорядочены по возрастанию важности:
+// TRACE < DEBUG < INFO < WARN < ERROR < PANIC < FATAL.
+//
+// Levels are ordered by increasing severity:
+// TRACE < DEBUG < INFO < WARN < ERROR < PANIC < FATAL.
+//
+type Level int
 
 const (
-	levelInfo  level = 100
-	levelError level = 200
-	levelPanic level = 300
+	LevelTrace Level = 20
+	LevelDebug Level = 50
+	LevelInfo  Level = 100
+	LevelWarn  Level = 150
+	LevelError Level = 200
+	LevelPanic Level = 300
+	LevelFatal Level = 400
 )
 
+// String : строковое представление уровня. | string representation of the level.
+//
+func (lvl Level) String() string {
+	return toStringLevel(lvl)
+}
+
+// MarshalText : implement encoding.TextMarshaler
+//
+func (lvl Level) MarshalText() ([]byte, error) {
+	return []byte(toStringLevel(lvl)), nil
+}
+
+// UnmarshalText : implement encoding.TextUnmarshaler
+//
+// Регистр не учитывается, 'WARNING' является синонимом 'WARN'.
+//
+// Case insensitive, 'WARNING' is a synonym for 'WARN'.
+//
+func (lvl *Level) UnmarshalText(text []byte) error {
+	switch strings.ToUpper(strings.TrimSpace(string(text))) {
+	case "TRACE":
+		*lvl = LevelTrace
+	case "DEBUG":
+		*lvl = LevelDebug
+	case "INFO":
+		*lvl = LevelInfo
+	case "WARN", "WARNING":
+		*lvl = LevelWarn
+	case "ERROR":
+		*lvl = LevelError
+	case "PANIC":
+		*lvl = LevelPanic
+	case "FATAL":
+		*lvl = LevelFatal
+	default:
+		return errors.New("Level.UnmarshalText : unknown level '" + string(text) + "'")
+	}
+	return nil
+}
+
 // iLogger : интерфейс логгера. | logger interface.
 //
 type iLogger interface {
@@ -55,7 +113,7 @@ type iLogger interface {
 	// that implements the 'iLogger' interface. Regulates the order
 	// of callingMode line creation, output and error handling.
 	//
-	add(data *logData, param ...string)
+	add(data *Entry, param ...string)
 
 	// createOutputString : метод, ответственный за создание строки лога. | method responsible for creating the callingMode line.
 	//
@@ -87,7 +145,7 @@ type iLogger interface {
 	// if the passed data is not valid or
 	// some error occurred while marshaling.
 	//
-	createOutputString(data *logData, param ...string) (*string, error)
+	createOutputString(data *Entry, param ...string) (*string, error)
 
 	// output : метод, выполняющий вывод лога. | method that performs callingMode output.
 	//
@@ -133,48 +191,195 @@ type iLogger interface {
 	errorOutput(out *string, err error)
 }
 
-type logData struct {
+// Entry : запись лога, которая передаётся накопителям и шаблонам.
+//         Поля 'Value', 'Level', 'Package', 'Date', 'Time', 'Func', 'Receiver', 'File', 'ShortFile', 'Line', 'Stack',
+//         'Error', 'ErrorType', 'Errors', 'Fields' доступны в шаблонах, например '{{.Level}}'.
+//
+//         log entry that is passed to outputs and templates.
+//         Fields 'Value', 'Level', 'Package', 'Date', 'Time', 'Func', 'Receiver', 'File', 'ShortFile', 'Line', 'Stack',
+//         'Error', 'ErrorType', 'Errors', 'Fields' are available in templates, for example '{{.Level}}'.
+//
+type Entry struct {
 	UserDataOriginal                        interface{}
-	Lvl                                     level
+	Lvl                                     Level
 	IsOption                                bool
 	Error                                   error
 	Value, Level, Package, Date, Func, Line string
+	// Ключ правила маршрутизации из 'Packages(...)', по которому прошёл лог.
+	// The key of the routing rule from 'Packages(...)' the log was routed by.
+	Route string
+	// Место вызова: полный путь файла, последняя директория и имя файла ("usecase/user.go"),
+	// тип получателя метода ("*UserService", пустой для функций). 'Func' содержит имя метода
+	// или функции вместе с замыканиями ("Create.func1"), 'Package' - путь импорта пакета.
+	// Call site: full file path, last directory and file name ("usecase/user.go"),
+	// method receiver type ("*UserService", empty for functions). 'Func' contains the method
+	// or function name along with closures ("Create.func1"), 'Package' - package import path.
+	File, ShortFile, Receiver string
+	// Стек вызовов, если он захватывается для уровня лога ('Logger.CaptureStack').
+	// Call stack if it's captured for the log level ('Logger.CaptureStack').
+	Stack Stack
+	// Если значение пользователя реализует 'error': 'Error' - сама ошибка, 'ErrorType' - её тип,
+	// 'Errors' - цепочка 'errors.Unwrap'. 'Value' в этом случае содержит сообщение ошибки.
+	// If the user value implements 'error': 'Error' is the error itself, 'ErrorType' - its type,
+	// 'Errors' - the 'errors.Unwrap' chain. 'Value' in this case contains the error message.
+	ErrorType string
+	Errors    ErrorChain
+	// Поля дочернего логгера ('Logger.With'). | Child logger fields ('Logger.With').
+	Fields Fields
+	// Время лога. 'Date' - то же время в формате накопителя ('.TimeFormat(...)'),
+	// по умолчанию - "Mon Jan _2 15:04:05 2006".
+	// Log time. 'Date' is the same time in the output format ('.TimeFormat(...)'),
+	// by default - "Mon Jan _2 15:04:05 2006".
+	Time time.Time
+
+	// Маршалинг выполняется лениво, только накопителем, принявшим лог по уровню.
+	// Marshaling is performed lazily, only by the output that accepted the log by level.
+	marshaling *entryMarshal
+
+	// Формат даты накопителя, для которого создано представление лога ('Entry.at').
+	// Date format of the output the log view is created for ('Entry.at').
+	clock *clock
+}
+
+// entryMarshal : общий для лога и его представлений результат маршалинга.
+//                marshaling result shared by the log and its views.
+//
+type entryMarshal struct {
+	once sync.Once
+	err  error
 }
 
-func newLogData(value interface{}, lvl level, date string) *logData {
-	log := new(logData)
+func newEntry(value interface{}, lvl Level, now time.Time) *Entry {
+	log := new(Entry)
 	log.UserDataOriginal = value
-	log.Date = date
+	log.Time = now
+	log.Date = now.Format(dateLayout)
 	log.Lvl = lvl
 	log.Level = toStringLevel(lvl)
+	log.marshaling = new(entryMarshal)
+	// Типизированный nil выводится как 'null', как и любое другое значение nil.
+	// Typed nil is output as 'null', like any other nil value.
+	if err, ok := value.(error); ok && !isNilValue(err) {
+		log.Error = err
+		log.Errors = errorChain(err)
+		log.ErrorType = log.Errors[0].Type
+	}
 	return log
 }
 
-func (log *logData) marshal(base *loggerBase) error {
-	out, err := base.createOutputString(log)
-	if err != nil {
-		log.Value = "marshal error"
-		return err
+func (log *Entry) marshal(base *loggerBase) error {
+	log.marshaling.once.Do(func() {
+		out, err := base.createOutputString(log)
+		if err != nil {
+			log.Value = "marshal error"
+			log.marshaling.err = err
+			return
+		}
+		log.Value = *out
+	})
+	return log.marshaling.err
+}
+
+// marshalErr : ошибка маршалинга значения пользователя. | user value marshaling error.
+//
+func (log *Entry) marshalErr() error {
+	return log.marshaling.err
+}
+
+// at : представление лога с датой в формате накопителя. Вызывается после 'marshal'.
+//      log view with the date in the output format. It's called after 'marshal'.
+//
+func (log *Entry) at(c *clock) *Entry {
+	if c == nil {
+		return log
 	}
-	log.Value = *out
-	return err
+	view := *log
+	view.Time = c.in(log.Time)
+	view.Date = c.format(log.Time)
+	view.clock = c
+	return &view
 }
 
-func (log *logData) setRuntimeInfo(skip int) *logData {
-	function, pckg, line := getRuntimeInfo(skip)
-	log.Func = function
-	log.Package = pckg
-	log.Line = line
+func (log *Entry) setRuntimeInfo(skip int) *Entry {
+	pcs := make([]uintptr, 1)
+	if runtime.Callers(skip, pcs) == 0 {
+		log.Func = "undefined func"
+		log.Package = "undefined package"
+		log.Line = "-1"
+		return log
+	}
+	frame, _ := runtime.CallersFrames(pcs).Next()
+	log.Package, log.Receiver, log.Func = splitFuncName(frame.Function)
+	log.File = frame.File
+	log.ShortFile = shortFile(frame.File)
+	log.Line = strconv.Itoa(frame.Line)
 	return log
 }
 
-func (log *logData) filledTemplate(tmpl *template.Template) *string {
+// outputFormat : шаблон, кодировщик и формат времени, с которыми инсталлер создаёт строки лога.
+//                Хранится для каждого ключа файла и каждого пакета консоли, поэтому '.Encoder(...)'
+//                и '.TimeFormat(...)' одного инсталлера не влияют на выход других.
+//
+//                template, encoder and time format with which the installer creates log lines.
+//                It's stored for each file key and each console package, so '.Encoder(...)'
+//                and '.TimeFormat(...)' of one installer don't affect the output of others.
+//
+type outputFormat struct {
+	tmpl    *template.Template
+	encoder Encoder
+	clock   *clock
+}
+
+// newOutputFormat : constructor
+//
+func newOutputFormat(tmpl *template.Template, setup *settings) *outputFormat {
+	return &outputFormat{
+		tmpl:    tmpl,
+		encoder: setup.encoder,
+		clock:   setup.clock,
+	}
+}
+
+// render : строка лога в этом формате. | log line in this format.
+//
+func (format *outputFormat) render(log *Entry) (*string, error) {
+	return format.renderTo(log, nil)
+}
+
+// renderTo : строка лога для потока 'writer', например, с цветом 'PrettyEncoder', если поток - терминал.
+//            log line for the stream 'writer', for example, with the 'PrettyEncoder' color if the stream is a terminal.
+//
+func (format *outputFormat) renderTo(log *Entry, writer io.Writer) (*string, error) {
+	encoder := format.encoder
+	if streamed, ok := encoder.(writerEncoder); ok {
+		encoder = streamed.forWriter(writer)
+	}
+	return log.encoded(format.tmpl, encoder, format.clock)
+}
+
+// encoded : строка лога через кодировщик, если он установлен, иначе через шаблон.
+//           log line via the encoder if it's set, otherwise via the template.
+//
+func (log *Entry) encoded(tmpl *template.Template, encoder Encoder, c *clock) (*string, error) {
+	log = log.at(c)
+	if encoder == nil {
+		return log.filledTemplate(tmpl), nil
+	}
+	out, err := encoder.Encode(log)
+	if err != nil {
+		return log.filledTemplate(getTextTemplate("base", BaseLogTemplate, BaseLogTemplate)), err
+	}
+	return &out, nil
+}
+
+func (log *Entry) filledTemplate(tmpl *template.Template) *string {
 	var (
 		out    = ""
 		buffer = new(bytes.Buffer)
 	)
 	err := tmpl.Execute(buffer, log)
 	if err != nil {
+		buffer.Reset()
 		baseTemplate := getTextTemplate("base", BaseLogTemplate, BaseLogTemplate)
 		_ = baseTemplate.Execute(buffer, log)
 	}
@@ -187,58 +392,97 @@ func getTextTemplate(name, str, alternative string) *template.Template {
 		textTemplate *template.Template
 	)
 	if str == "" {
-		textTemplate, _ = template.New(name).Parse(alternative)
+		textTemplate, _ = template.New(name).Funcs(TemplateFuncs()).Parse(alternative)
 	} else {
-		t, err := template.New(name).Parse(str)
+		t, err := template.New(name).Funcs(TemplateFuncs()).Parse(str)
 		if err != nil {
-			textTemplate, _ = template.New("console").Parse(alternative)
+			textTemplate, _ = template.New(name).Funcs(TemplateFuncs()).Parse(alternative)
+		} else {
+			textTemplate = t
 		}
-		textTemplate = t
 	}
 	return textTemplate
 }
 
-func toStringLevel(lvl level) string {
+func toStringLevel(lvl Level) string {
 	switch lvl {
-	case levelInfo:
+	case LevelTrace:
+		return "TRACE"
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
 		return "INFO"
-	case levelError:
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
 		return "ERROR"
-	case levelPanic:
+	case LevelPanic:
 		return "PANIC"
+	case LevelFatal:
+		return "FATAL"
 	default:
 		return "NON"
 	}
 }
 
-func getRuntimeInfo(skip int) (string, string, string) {
-	var (
-		function = "undefined func"
-		line     = "-1"
-		pckg     = "undefined package"
-	)
-	pc, _, lineInt, ok := runtime.Caller(skip)
-	if !ok {
-		return function, pckg, line
-	}
-	function = runtime.FuncForPC(pc).Name()
-	if strings.Contains(function, "/") {
-		//
-		split := strings.Split(function, "/")
-		function = split[len(split)-1]
-		//
-		functionSplit := strings.Split(function, ".")
-		function = functionSplit[len(functionSplit)-1]
-		//
-		split = split[0:len(split)-1]
-		split = append(split, functionSplit[0])
-		pckg = strings.Join(split, "/")
-	} else {
-		if strings.Contains(function, ".") {
-			functionSplit := strings.Split(function, ".")
-			function = functionSplit[len(functionSplit)-1]
-			pckg = functionSplit[0]
+// splitFuncName : разделяет полное имя функции из 'runtime' на путь импорта пакета, тип получателя и имя:
+//                 "github.com/user/app/usecase.(*UserService).Create.func1" -> "github.com/user/app/usecase", "*UserService", "Create.func1".
+//
+//                 splits the full function name from 'runtime' into the package import path, receiver type and name:
+//                 "github.com/user/app/usecase.(*UserService).Create.func1" -> "github.com/user/app/usecase", "*UserService", "Create.func1".
+//
+func splitFuncName(name string) (string, string, string) {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return "undefined package", "", name
+	}
+	dot += slash + 1
+	// Точки в последнем элементе пути импорта экранируются: "gopkg.in/yaml%2ev2".
+	// Dots in the last element of the import path are escaped: "gopkg.in/yaml%2ev2".
+	pckg := strings.Replace(name[:dot], "%2e", ".", -1)
+	function := name[dot+1:]
+	receiver := ""
+	if strings.HasPrefix(function, "(") {
+		if end := strings.Index(function, ")."); end > 0 {
+			receiver = function[1:end]
+			function = function[end+2:]
+		}
+	} else if parts := strings.SplitN(function, ".", 2); len(parts) == 2 && !isClosureName(parts[1]) {
+		// Метод с получателем-значением: "usecase.UserService.Create".
+		// Method with a value receiver: "usecase.UserService.Create".
+		receiver = parts[0]
+		function = parts[1]
+	}
+	return pckg, receiver, function
+}
+
+// isClosureName : имя начинается с замыкания ("func1", "1") или безымянного элемента ("glob..func1").
+//                 the name starts with a closure ("func1", "1") or an unnamed element ("glob..func1").
+//
+func isClosureName(name string) bool {
+	segment := strings.SplitN(name, ".", 2)[0]
+	segment = strings.TrimPrefix(segment, "func")
+	if segment == "" {
+		return true
+	}
+	for _, r := range segment {
+		if r < '0' || r > '9' {
+			return false
 		}
 	}
-	return function, pckg, strconv.Itoa(lineInt)
+	return true
+}
+
+// shortFile : последняя директория и имя файла. | last directory and file name.
+//
+func shortFile(file string) string {
+	slash := strings.LastIndex(file, "/")
+	if slash < 0 {
+		return file
+	}
+	if parent := strings.LastIndex(file[:slash], "/"); parent >= 0 {
+		return file[parent+1:]
+	}
+	return file
 }
@@ -0,0 +1,85 @@
+package gologster
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestSplitFuncName(t *testing.T) {
+	const app = "github.com/user/app/usecase"
+	tests := []struct {
+		name, pckg, receiver, function string
+	}{
+		{name: app + ".(*UserService).Create", pckg: app, receiver: "*UserService", function: "Create"},
+		{name: app + ".(*UserService).Create.func1", pckg: app, receiver: "*UserService", function: "Create.func1"},
+		{name: app + ".UserService.Create", pckg: app, receiver: "UserService", function: "Create"},
+		{name: app + ".UserService.Create.func1", pckg: app, receiver: "UserService", function: "Create.func1"},
+		{name: app + ".Create", pckg: app, function: "Create"},
+		{name: app + ".Create.func1", pckg: app, function: "Create.func1"},
+		{name: app + ".Create.func1.2", pckg: app, function: "Create.func1.2"},
+		{name: app + ".glob..func1", pckg: app, function: "glob..func1"},
+		{name: app + ".init.0", pckg: app, function: "init.0"},
+		{name: app + ".(*List[...]).Push", pckg: app, receiver: "*List[...]", function: "Push"},
+		{name: "gopkg.in/yaml%2ev2.Unmarshal", pckg: "gopkg.in/yaml.v2", function: "Unmarshal"},
+		{name: "gopkg.in/yaml%2ev2.(*decoder).unmarshal", pckg: "gopkg.in/yaml.v2", receiver: "*decoder", function: "unmarshal"},
+		{name: "main.main", pckg: "main", function: "main"},
+		{name: "main.main.func1", pckg: "main", function: "main.func1"},
+		{name: "github.com/user/app/nodot", pckg: "undefined package", function: "github.com/user/app/nodot"},
+		{name: "nodot", pckg: "undefined package", function: "nodot"},
+	}
+	for _, test := range tests {
+		pckg, receiver, function := splitFuncName(test.name)
+		if pckg != test.pckg || receiver != test.receiver || function != test.function {
+			t.Errorf("%s : got (%q, %q, %q), want (%q, %q, %q)", test.name,
+				pckg, receiver, function, test.pckg, test.receiver, test.function)
+		}
+	}
+}
+
+func TestIsClosureName(t *testing.T) {
+	tests := map[string]bool{
+		"func1":        true,
+		"func12.3":     true,
+		"1":            true,
+		".func1":       true,
+		"":             true,
+		"Create":       false,
+		"Create.func1": false,
+		"function":     false,
+		"funcA":        false,
+	}
+	for name, want := range tests {
+		if got := isClosureName(name); got != want {
+			t.Errorf("%q : got %v, want %v", name, got, want)
+		}
+	}
+}
+
+type funcNameTester struct{}
+
+func (funcNameTester) value() string { return callerName() }
+
+func (*funcNameTester) pointer() string { return callerName() }
+
+func callerName() string {
+	pc, _, _, _ := runtime.Caller(1)
+	return runtime.FuncForPC(pc).Name()
+}
+
+func TestSplitRuntimeFuncName(t *testing.T) {
+	const pckg = "github.com/RobertGumpert/logster"
+	tests := []struct {
+		name, receiver, function string
+	}{
+		{name: funcNameTester{}.value(), receiver: "funcNameTester", function: "value"},
+		{name: new(funcNameTester).pointer(), receiver: "*funcNameTester", function: "pointer"},
+		{name: func() string { return callerName() }(), function: "TestSplitRuntimeFuncName.func1"},
+	}
+	for _, test := range tests {
+		gotPckg, receiver, function := splitFuncName(test.name)
+		if gotPckg != pckg || receiver != test.receiver || function != test.function {
+			t.Errorf("%s : got (%q, %q, %q), want (%q, %q, %q)", test.name,
+				gotPckg, receiver, function, pckg, test.receiver, test.function)
+		}
+	}
+}
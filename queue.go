@@ -0,0 +1,157 @@
+package gologster
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// Overflow : поведение при переполнении очереди файла 'loggerFileMultithreading'.
+//            behavior when the 'loggerFileMultithreading' file queue overflows.
+//
+type Overflow int
+
+const (
+	// OverflowBlock : горутина-писатель ждёт освобождения места в очереди.
+	//                 the writer goroutine waits for free space in the queue.
+	OverflowBlock Overflow = iota
+	// OverflowBlockTimeout : горутина-писатель ждёт не дольше 'Queue.Timeout', затем лог отбрасывается.
+	//                        the writer goroutine waits no longer than 'Queue.Timeout', then the log is dropped.
+	OverflowBlockTimeout
+	// OverflowDropNewest : отбрасывается новый лог.
+	//                      the new log is dropped.
+	OverflowDropNewest
+	// OverflowDropOldest : отбрасывается самый старый лог в очереди.
+	//                      the oldest log in the queue is dropped.
+	OverflowDropOldest
+	// OverflowSpill : новый лог записывается в файл 'Queue.SpillPath'.
+	//                 the new log is written to the file 'Queue.SpillPath'.
+	OverflowSpill
+)
+
+// defaultQueueCapacity : размер очереди файла по умолчанию.
+//                        default file queue size.
+//
+const defaultQueueCapacity = 1000
+
+// Queue : параметры очереди файла 'loggerFileMultithreading'.
+//         'loggerFileMultithreading' file queue parameters.
+//
+type Queue struct {
+	// Размер очереди, по умолчанию 1000.
+	// Queue size, 1000 by default.
+	Capacity int
+	Overflow Overflow
+	// Время ожидания для 'OverflowBlockTimeout'.
+	// Waiting time for 'OverflowBlockTimeout'.
+	Timeout time.Duration
+	// Файл для 'OverflowSpill'.
+	// File for 'OverflowSpill'.
+	SpillPath string
+}
+
+// QueueStats : состояние очереди файла. | file queue state.
+//
+type QueueStats struct {
+	// Количество логов в очереди и в процессе записи.
+	// Number of logs in the queue and being written.
+	Depth    int64
+	Capacity int
+	// Количество отброшенных логов и логов, записанных в файл 'Queue.SpillPath'.
+	// Number of dropped logs and logs written to the file 'Queue.SpillPath'.
+	Dropped, Spilled int64
+}
+
+// enqueue : помещает строку в очередь файла согласно 'Queue.Overflow'.
+//           Возвращает 'false', если строка не попала в очередь.
+//
+//           puts the line into the file queue according to 'Queue.Overflow'.
+//           Returns 'false' if the line didn't get into the queue.
+//
+func (logger *loggerFileMultithreading) enqueue(file *fileAgent, out *string) bool {
+	switch file.queue.Overflow {
+	case OverflowBlockTimeout:
+		timer := time.NewTimer(file.queue.Timeout)
+		defer timer.Stop()
+		select {
+		case file.channel <- out:
+			return true
+		case <-file.quit:
+			return false
+		case <-timer.C:
+			atomic.AddInt64(&file.dropped, 1)
+			return false
+		}
+	case OverflowDropNewest:
+		select {
+		case file.channel <- out:
+			return true
+		default:
+			atomic.AddInt64(&file.dropped, 1)
+			return false
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case file.channel <- out:
+				return true
+			case <-file.quit:
+				return false
+			default:
+			}
+			select {
+			case <-file.channel:
+				atomic.AddInt64(&file.pending, -1)
+				atomic.AddInt64(&file.dropped, 1)
+			default:
+			}
+		}
+	case OverflowSpill:
+		select {
+		case file.channel <- out:
+			return true
+		default:
+		}
+		if _, err := file.spill.WriteString(*out + "\n"); err != nil {
+			atomic.AddInt64(&file.dropped, 1)
+			logger.errorOutput(out, errors.New("loggerFileMultithreading.enqueue spill : "+err.Error()))
+			return false
+		}
+		atomic.AddInt64(&file.spilled, 1)
+		return false
+	default:
+		select {
+		case file.channel <- out:
+			return true
+		case <-file.quit:
+			return false
+		}
+	}
+}
+
+// stats : состояние очередей всех файлов. | state of all file queues.
+//
+func (logger *loggerFileMultithreading) stats() map[string]QueueStats {
+	stats := make(map[string]QueueStats, len(logger.config))
+	for key, file := range logger.config {
+		stats[key] = QueueStats{
+			Depth:    atomic.LoadInt64(&file.pending),
+			Capacity: cap(file.channel),
+			Dropped:  atomic.LoadInt64(&file.dropped),
+			Spilled:  atomic.LoadInt64(&file.spilled),
+		}
+	}
+	return stats
+}
+
+// QueueStats : состояние очередей 'loggerFileMultithreading' по ключам файлов.
+//              state of 'loggerFileMultithreading' queues by file keys.
+//
+func (logger *Logger) QueueStats() map[string]QueueStats {
+	logger.mx.RLock()
+	defer logger.mx.RUnlock()
+	if logger.modeFileMulti == nil {
+		return make(map[string]QueueStats)
+	}
+	return logger.modeFileMulti.stats()
+}
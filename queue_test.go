@@ -0,0 +1,152 @@
+package gologster
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueueOverflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		queue    Queue
+		dropped  int64
+		spilled  int64
+		written  []string
+		overflow []string
+	}{
+		{
+			name:    "drop_newest",
+			queue:   Queue{Capacity: 2, Overflow: OverflowDropNewest},
+			dropped: 3, written: []string{"0", "1", "2"},
+		},
+		{
+			name:    "drop_oldest",
+			queue:   Queue{Capacity: 2, Overflow: OverflowDropOldest},
+			dropped: 3, written: []string{"0", "4", "5"},
+		},
+		{
+			name:    "block_timeout",
+			queue:   Queue{Capacity: 2, Overflow: OverflowBlockTimeout, Timeout: time.Millisecond},
+			dropped: 3, written: []string{"0", "1", "2"},
+		},
+		{
+			name:    "spill",
+			queue:   Queue{Capacity: 2, Overflow: OverflowSpill},
+			spilled: 3, written: []string{"0", "1", "2"}, overflow: []string{"3", "4", "5"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "gologster")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "a.txt")
+			logger, unblock := stalledQueue(t, path, test.queue)
+			defer logger.Close(context.Background())
+			defer unblock()
+			// Очередь заполнена: одна строка у горутины-читателя и две в канале.
+			// The queue is full: one line at the reader goroutine and two in the channel.
+			for i := 3; i < 6; i++ {
+				logger.Info(i, OptionFileMulti("a"))
+			}
+			stats := logger.QueueStats()["a"]
+			if stats.Depth != 3 || stats.Dropped != test.dropped || stats.Spilled != test.spilled {
+				t.Errorf("got %+v, want depth 3, dropped %d, spilled %d", stats, test.dropped, test.spilled)
+			}
+			unblock()
+			flushQueue(t, logger)
+			if got := readLines(t, path); strings.Join(got, ",") != strings.Join(test.written, ",") {
+				t.Errorf("file : got %v, want %v", got, test.written)
+			}
+			if test.overflow != nil {
+				if got := readLines(t, path+".overflow"); strings.Join(got, ",") != strings.Join(test.overflow, ",") {
+					t.Errorf("overflow file : got %v, want %v", got, test.overflow)
+				}
+			}
+		})
+	}
+}
+
+func TestQueueBlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gologster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a.txt")
+	logger, unblock := stalledQueue(t, path, Queue{Capacity: 2, Overflow: OverflowBlock})
+	defer logger.Close(context.Background())
+	defer unblock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info(3, OptionFileMulti("a"))
+	}()
+	select {
+	case <-done:
+		t.Fatal("writer isn't blocked by the full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if stats := logger.QueueStats()["a"]; stats.Depth != 4 || stats.Dropped != 0 {
+		t.Errorf("got %+v, want depth 4 with the blocked writer", stats)
+	}
+	unblock()
+	<-done
+	flushQueue(t, logger)
+	if got := readLines(t, path); strings.Join(got, ",") != "0,1,2,3" {
+		t.Errorf("file : got %v, want [0 1 2 3]", got)
+	}
+}
+
+// stalledQueue : логгер с ключом файла "a", горутина-читатель которого остановлена на записи
+//                первой строки, а в очереди ещё две строки. 'unblock' возобновляет запись.
+//
+//                logger with the file key "a" whose reader goroutine is stopped on writing
+//                the first line, and two more lines are in the queue. 'unblock' resumes writing.
+//
+func stalledQueue(t *testing.T, path string, queue Queue) (*Logger, func()) {
+	logger := Default(DefaultFileMulti("{{.Value}}", map[string]string{"a": path}).Queue(queue))
+	file := logger.modeFileMulti.config["a"]
+	// Горутина-читатель ожидает 'loggerBaseFile.mx' при записи строки.
+	// The reader goroutine waits for 'loggerBaseFile.mx' when writing a line.
+	logger.baseFile.mx.Lock()
+	logger.Info(0, OptionFileMulti("a"))
+	for len(file.channel) != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	logger.Info(1, OptionFileMulti("a"))
+	logger.Info(2, OptionFileMulti("a"))
+	var once bool
+	return logger, func() {
+		if !once {
+			once = true
+			logger.baseFile.mx.Unlock()
+		}
+	}
+}
+
+func flushQueue(t *testing.T, logger *Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := logger.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if stats := logger.QueueStats()["a"]; stats.Depth != 0 {
+		t.Errorf("got depth %d after Flush, want 0", stats.Depth)
+	}
+}
+
+func readLines(t *testing.T, path string) []string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Fields(string(data))
+}
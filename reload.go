@@ -0,0 +1,167 @@
+package gologster
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync/atomic"
+	"time"
+)
+
+// ApplyConfig : заменяет маршруты и накопители логгера на описанные в документе конфигурации
+//               (формат 'FromConfig') одной операцией. Если документ содержит ошибку,
+//               он отклоняется и логгер продолжает работать по старой конфигурации.
+//
+//               Документ также отклоняется, если его инсталлер вернул ошибку.
+//
+//               Строки, уже находящиеся в очередях 'loggerFileMultithreading', дописываются
+//               в свои файлы до замены, поэтому на время замены логирование ожидает.
+//               Пользовательские накопители ('DefaultSink') сохраняются, захват стека ('CaptureStack')
+//               заменяется настройками 'stack' документа. Изменения 'AdminHandler' (с оставшимся 'ttl')
+//               и извлекатели 'PackageContext' переносятся в маршруты с тем же правилом.
+//
+//               replaces the routes and outputs of the logger with the ones described in the configuration
+//               document ('FromConfig' format) in one operation. If the document contains an error,
+//               it's rejected and the logger continues to work with the old configuration.
+//
+//               The document is also rejected if its installer returns an error.
+//
+//               Lines already in the 'loggerFileMultithreading' queues are written out to their files
+//               before the swap, so logging waits during the swap.
+//               User sinks ('DefaultSink') are kept, stack capture ('CaptureStack')
+//               is replaced with the 'stack' settings of the document. 'AdminHandler' changes (with the remaining
+//               'ttl') and 'PackageContext' extractors are carried over to the routes with the same rule.
+//
+func (logger *Logger) ApplyConfig(reader io.Reader) error {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.New("Logger.ApplyConfig : " + err.Error())
+	}
+	loaded, err := parseConfig(data)
+	if err != nil {
+		return err
+	}
+	// Новая конфигурация устанавливается в отдельный логгер без блокировки,
+	// затем его состояние переносится под 'mx.Lock()'.
+	// The new configuration is installed into a separate logger without the lock,
+	// then its state is moved under 'mx.Lock()'.
+	next, err := loaded.build(logger.base)
+	if err != nil {
+		next.discard()
+		return errors.New("Logger.ApplyConfig : " + err.Error())
+	}
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	if err := logger.live("Logger.ApplyConfig"); err != nil {
+		next.discard()
+		return err
+	}
+	// Очереди дописываются до замены, чтобы старые и новые открытые файлы и ротации
+	// одного и того же пути не работали одновременно. Ключи 'loggerFileMutex' удаляются после,
+	// так как в режиме 'Default' оба логгера используют одни и те же открытые файлы.
+	// The queues are written out before the swap, so that the old and new open files and rotations
+	// of the same path don't work at the same time. The 'loggerFileMutex' keys are removed afterwards,
+	// since in 'Default' mode both loggers use the same open files.
+	if logger.modeFileMulti != nil {
+		logger.modeFileMulti.drain()
+	}
+	if logger.modeFileMutex != nil {
+		logger.modeFileMutex.retire()
+	}
+	previous := logger.pckgs
+	logger.baseFile = next.baseFile
+	logger.baseConsole = next.baseConsole
+	logger.modeConsole = next.modeConsole
+	logger.modeFileMulti = next.modeFileMulti
+	logger.modeFileMutex = next.modeFileMutex
+	logger.pckgs = next.pckgs
+	logger.level = next.level
+	logger.stack = next.stack
+	for _, sink := range logger.sinks {
+		// Маршруты пакетов к накопителям заменены документом вместе с их форматами.
+		// The package routes to the sinks are replaced by the document together with their formats.
+		sink.packages = make(map[string]*outputFormat)
+		logger.threshold(sink.level)
+	}
+	logger.carryOverrides(previous)
+	logger.carryExtractors(previous)
+	return nil
+}
+
+// discard : останавливает горутины и закрывает файлы логгера, который не был применён.
+//           stops the goroutines and closes the files of a logger that wasn't applied.
+//
+func (logger *Logger) discard() {
+	if logger.modeFileMulti != nil {
+		logger.modeFileMulti.stop()
+	}
+	for _, baseFile := range logger.baseFiles() {
+		_ = baseFile.close()
+	}
+}
+
+// Watch : каждые 'interval' читает файл конфигурации 'path' и применяет его ('ApplyConfig'),
+//         если его содержимое изменилось. Текущее содержимое файла считается уже применённым,
+//         поэтому обычно логгер создаётся через 'FromFile(path)'. Ошибки чтения и отклонённые
+//         документы выводятся в консоль. Опрос завершается при вызове 'Close'.
+//
+//         reads the configuration file 'path' every 'interval' and applies it ('ApplyConfig')
+//         if its content has changed. The current content of the file is considered already applied,
+//         so the logger is usually created via 'FromFile(path)'. Read errors and rejected
+//         documents are output to the console. Polling stops when 'Close' is called.
+//
+func (logger *Logger) Watch(path string, interval time.Duration) error {
+	if interval <= 0 {
+		return errors.New("Logger.Watch : interval must be positive. ")
+	}
+	applied, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.New("Logger.Watch : " + err.Error())
+	}
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	if err := logger.live("Logger.Watch"); err != nil {
+		return err
+	}
+	if logger.watch != nil {
+		return errors.New("Logger.Watch : configuration file is already watched. ")
+	}
+	logger.watch = make(chan struct{})
+	go logger.watcher(path, interval, applied, logger.watch)
+	return nil
+}
+
+// watcher : горутина опроса файла конфигурации. | configuration file polling goroutine.
+//
+func (logger *Logger) watcher(path string, interval time.Duration, applied []byte, quit chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			logger.installError(errors.New("Logger.Watch : " + err.Error()))
+			continue
+		}
+		if bytes.Equal(data, applied) {
+			continue
+		}
+		// Отклонённый документ тоже запоминается, чтобы не выводить ошибку при каждом опросе.
+		// A rejected document is remembered too, so as not to output the error on every poll.
+		applied = data
+		if err := logger.ApplyConfig(bytes.NewReader(data)); err != nil {
+			if atomic.LoadInt32(&logger.closed) == 1 {
+				return
+			}
+			if _, ok := err.(*ConfigError); ok {
+				err = errors.New(path + ":" + err.Error())
+			}
+			logger.installError(errors.New("Logger.Watch : configuration is rejected : " + err.Error()))
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package gologster
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyConfigWritesQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gologster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var (
+		path  = filepath.Join(dir, "a.txt")
+		files = map[string]string{"a": path}
+		out   bytes.Buffer
+	)
+	// Настройка из README: оба файловых логгера используют один ключ и один открытый файл.
+	// Setup from README: both file loggers use the same key and the same open file.
+	logger := Default(
+		DefaultConsoleSimple(BaseLogTemplate).Writers(&out, &out),
+		DefaultFileMutex(BaseLogTemplate, files),
+		DefaultFileMulti(BaseLogTemplate, files),
+	)
+	const count = 5000
+	for i := 0; i < count; i++ {
+		logger.Info(i, OptionFileMulti("a"))
+	}
+	config := `{"default": [{"type": "file_multi", "files": {"b": "` + filepath.Join(dir, "b.txt") + `"}}]}`
+	if err := logger.ApplyConfig(strings.NewReader(config)); err != nil {
+		t.Fatal(err)
+	}
+	if lines := countLines(t, path); lines != count {
+		t.Errorf("got %d lines, want %d", lines, count)
+	}
+	if err := logger.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("unexpected console output : %q", out.String())
+	}
+}
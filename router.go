@@ -0,0 +1,271 @@
+package gologster
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Ключи карты 'Packages(...)' задают правила маршрутизации по пути импорта пакета,
+// в котором был вызван метод логирования:
+//
+//   "=github.com/user/app/repository/user"  - точное совпадение пути ('RouteExact');
+//   "github.com/user/app/repository/..."    - путь и все вложенные пакеты ('RoutePrefix');
+//   "github.com/user/app/*/user"            - шаблон 'path.Match' ('RouteGlob');
+//   "~^github.com/user/app/.*/user$"        - регулярное выражение ('RouteRegexp');
+//   "/repository/user"                      - вхождение подстроки (прежнее поведение);
+//   "*"                                     - все остальные пакеты ('RouteFallback').
+//
+// Если подходит несколько правил, выбирается одно, в порядке приоритета:
+// точное совпадение, затем префикс, шаблон, регулярное выражение, подстрока и 'RouteFallback'.
+// Внутри одного вида побеждает более длинное правило, при равной длине - меньшее лексикографически.
+// Поэтому один и тот же лог всегда идёт по одному и тому же маршруту.
+//
+// Keys of the 'Packages(...)' map define routing rules by the import path of the package
+// where the logging method was called:
+//
+//   "=github.com/user/app/repository/user"  - exact path match ('RouteExact');
+//   "github.com/user/app/repository/..."    - the path and all nested packages ('RoutePrefix');
+//   "github.com/user/app/*/user"            - 'path.Match' pattern ('RouteGlob');
+//   "~^github.com/user/app/.*/user$"        - regular expression ('RouteRegexp');
+//   "/repository/user"                      - substring occurrence (previous behavior);
+//   "*"                                     - all other packages ('RouteFallback').
+//
+// If several rules match, one is chosen in order of precedence:
+// exact match, then prefix, glob, regular expression, substring and 'RouteFallback'.
+// Within one kind, the longer rule wins, with equal length - the lexicographically smaller one.
+// Therefore the same log always takes the same route.
+
+// ruleKind : вид правила маршрутизации, в порядке приоритета.
+//            kind of routing rule, in order of precedence.
+//
+type ruleKind int
+
+const (
+	ruleExact ruleKind = iota
+	rulePrefix
+	ruleGlob
+	ruleRegexp
+	ruleContains
+	ruleFallback
+)
+
+// RouteFallback : правило для логов из пакетов, не подошедших ни под одно другое правило.
+//                 rule for logs from packages that didn't match any other rule.
+//
+const RouteFallback = "*"
+
+// RouteExact : правило точного совпадения пути импорта.
+//              exact import path match rule.
+//
+func RouteExact(importPath string) string {
+	return "=" + importPath
+}
+
+// RoutePrefix : правило для пути импорта и всех вложенных пакетов.
+//               rule for the import path and all nested packages.
+//
+func RoutePrefix(importPath string) string {
+	return strings.TrimSuffix(importPath, "/") + "/..."
+}
+
+// RouteGlob : правило по шаблону 'path.Match'.
+//             rule by 'path.Match' pattern.
+//
+func RouteGlob(pattern string) string {
+	return pattern
+}
+
+// RouteRegexp : правило по регулярному выражению.
+//               rule by regular expression.
+//
+func RouteRegexp(expr string) string {
+	return "~" + expr
+}
+
+// rule : правило маршрутизации и опции, через которые выводятся подходящие логи.
+//        routing rule and options through which matching logs are output.
+//
+type rule struct {
+	key     string
+	kind    ruleKind
+	pattern string
+	re      *regexp.Regexp
+	routes  []route
+
+	// Временное изменение уровня или отключение маршрута через 'AdminHandler'.
+	// Temporary level change or muting of the route via 'AdminHandler'.
+	override *ruleOverride
+
+	// Извлекатели полей из контекста ('PackageContext').
+	// Extractors of fields from the context ('PackageContext').
+	extractors []ContextExtractor
+}
+
+// newRule : constructor
+//
+func newRule(key string) (*rule, error) {
+	r := &rule{key: key}
+	switch {
+	case key == RouteFallback:
+		r.kind = ruleFallback
+	case strings.HasPrefix(key, "="):
+		r.kind = ruleExact
+		r.pattern = strings.TrimPrefix(key, "=")
+	case strings.HasPrefix(key, "~"):
+		re, err := regexp.Compile(strings.TrimPrefix(key, "~"))
+		if err != nil {
+			return nil, errors.New("router : rule '" + key + "' : " + err.Error())
+		}
+		r.kind = ruleRegexp
+		r.pattern = re.String()
+		r.re = re
+	case strings.HasSuffix(key, "..."):
+		r.kind = rulePrefix
+		r.pattern = strings.TrimSuffix(key, "...")
+	case strings.ContainsAny(key, "*?["):
+		if _, err := path.Match(key, ""); err != nil {
+			return nil, errors.New("router : rule '" + key + "' : " + err.Error())
+		}
+		r.kind = ruleGlob
+		r.pattern = key
+	default:
+		r.kind = ruleContains
+		r.pattern = key
+	}
+	return r, nil
+}
+
+// match : проверяет, подходит ли путь импорта под правило.
+//         checks whether the import path matches the rule.
+//
+func (r *rule) match(importPath string) bool {
+	switch r.kind {
+	case ruleExact:
+		return importPath == r.pattern
+	case rulePrefix:
+		if strings.HasSuffix(r.pattern, "/") && importPath == strings.TrimSuffix(r.pattern, "/") {
+			return true
+		}
+		return strings.HasPrefix(importPath, r.pattern)
+	case ruleGlob:
+		matched, _ := path.Match(r.pattern, importPath)
+		return matched
+	case ruleRegexp:
+		return r.re.MatchString(importPath)
+	case ruleFallback:
+		return true
+	default:
+		return strings.Contains(importPath, r.pattern)
+	}
+}
+
+// kindName : название вида правила. | name of the rule kind.
+//
+func (r *rule) kindName() string {
+	switch r.kind {
+	case ruleExact:
+		return "exact"
+	case rulePrefix:
+		return "prefix"
+	case ruleGlob:
+		return "glob"
+	case ruleRegexp:
+		return "regexp"
+	case ruleFallback:
+		return "fallback"
+	default:
+		return "contains"
+	}
+}
+
+// less : порядок проверки правил. | order of checking rules.
+//
+func (r *rule) less(other *rule) bool {
+	if r.kind != other.kind {
+		return r.kind < other.kind
+	}
+	if len(r.pattern) != len(other.pattern) {
+		return len(r.pattern) > len(other.pattern)
+	}
+	return r.key < other.key
+}
+
+// router : упорядоченный список правил маршрутизации.
+//          ordered list of routing rules.
+//
+type router struct {
+	rules []*rule
+	keys  map[string]*rule
+}
+
+// newRouter : constructor
+//
+func newRouter() *router {
+	r := new(router)
+	r.keys = make(map[string]*rule)
+	return r
+}
+
+// rule : возвращает правило по ключу, создавая его при необходимости.
+//        returns the rule by key, creating it if necessary.
+//
+func (r *router) rule(key string) (*rule, error) {
+	if existing, exist := r.keys[key]; exist {
+		return existing, nil
+	}
+	created, err := newRule(key)
+	if err != nil {
+		return nil, err
+	}
+	r.keys[key] = created
+	r.rules = append(r.rules, created)
+	sort.SliceStable(r.rules, func(i, j int) bool {
+		return r.rules[i].less(r.rules[j])
+	})
+	return created, nil
+}
+
+// add : добавляет опцию в маршрут правила 'key'.
+//       adds an option to the route of the rule 'key'.
+//
+func (r *router) add(key string, route route) error {
+	matched, err := r.rule(key)
+	if err != nil {
+		return err
+	}
+	matched.routes = append(matched.routes, route)
+	return nil
+}
+
+// remove : удаляет правило 'key' вместе с его маршрутом.
+//          removes the rule 'key' together with its route.
+//
+func (r *router) remove(key string) bool {
+	removed, exist := r.keys[key]
+	if !exist {
+		return false
+	}
+	delete(r.keys, key)
+	for i, candidate := range r.rules {
+		if candidate == removed {
+			r.rules = append(r.rules[:i], r.rules[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// match : первое по приоритету правило, подходящее под путь импорта.
+//         the first rule by precedence that matches the import path.
+//
+func (r *router) match(importPath string) *rule {
+	for _, candidate := range r.rules {
+		if candidate.match(importPath) {
+			return candidate
+		}
+	}
+	return nil
+}
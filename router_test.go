@@ -0,0 +1,89 @@
+package gologster
+
+import (
+	"testing"
+)
+
+func TestRouterPrecedence(t *testing.T) {
+	const importPath = "github.com/user/app/repository/user"
+	var (
+		r = newRouter()
+		// В порядке приоритета. | In order of precedence.
+		keys = []string{
+			RouteExact(importPath),
+			RoutePrefix("github.com/user/app/repository"),
+			RoutePrefix("github.com/user/app"),
+			RouteGlob("github.com/user/app/*/user"),
+			RouteRegexp("^github.com/user/.*/user$"),
+			RouteRegexp("user$"),
+			"/repository/user",
+			"/user",
+			RouteFallback,
+		}
+		// Не подходят под путь импорта. | Don't match the import path.
+		others = []string{
+			RouteExact("github.com/user/app/repository"),
+			RoutePrefix("github.com/user/application"),
+			RouteGlob("github.com/user/*/user"),
+			RouteRegexp("^user"),
+			"/repository/users",
+		}
+	)
+	for i := len(keys) - 1; i >= 0; i-- {
+		if err := r.add(keys[i], route{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, key := range others {
+		if err := r.add(key, route{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, key := range keys {
+		matched := r.match(importPath)
+		if matched == nil || matched.key != key {
+			t.Fatalf("got %v, want '%s'", matched, key)
+		}
+		r.remove(key)
+	}
+	if matched := r.match(importPath); matched != nil {
+		t.Errorf("got '%s' without fallback, want no rule", matched.key)
+	}
+}
+
+func TestRouterRule(t *testing.T) {
+	tests := []struct {
+		key        string
+		importPath string
+		kind       ruleKind
+		match      bool
+	}{
+		{key: "=a/b", importPath: "a/b", kind: ruleExact, match: true},
+		{key: "=a/b", importPath: "a/b/c", kind: ruleExact},
+		{key: "a/b/...", importPath: "a/b", kind: rulePrefix, match: true},
+		{key: "a/b/...", importPath: "a/b/c/d", kind: rulePrefix, match: true},
+		{key: "a/b/...", importPath: "a/bc", kind: rulePrefix},
+		{key: "a/*/c", importPath: "a/b/c", kind: ruleGlob, match: true},
+		{key: "a/*/c", importPath: "a/b/b/c", kind: ruleGlob},
+		{key: "~^a/.+/c$", importPath: "a/b/b/c", kind: ruleRegexp, match: true},
+		{key: "b/c", importPath: "a/b/c", kind: ruleContains, match: true},
+		{key: "*", importPath: "a", kind: ruleFallback, match: true},
+	}
+	for _, test := range tests {
+		r, err := newRule(test.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.kind != test.kind {
+			t.Errorf("%s : got kind '%s', want %d", test.key, r.kindName(), test.kind)
+		}
+		if got := r.match(test.importPath); got != test.match {
+			t.Errorf("%s : match('%s') = %v, want %v", test.key, test.importPath, got, test.match)
+		}
+	}
+	for _, key := range []string{"~(", "a/[b"} {
+		if _, err := newRule(key); err == nil {
+			t.Errorf("%s : want error", key)
+		}
+	}
+}
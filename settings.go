@@ -0,0 +1,420 @@
+package gologster
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// settings : дополнительные настройки, которые применяются к инсталлеру
+//            ('DefaultInstaller', 'PackageInstaller') в момент его вызова.
+//            Задаются через методы инсталлеров, например:
+//            'DefaultFileMutex(...).Level(LevelError)'.
+//
+//            additional settings applied to an installer
+//            ('DefaultInstaller', 'PackageInstaller') at the moment it is called.
+//            They are set via installer methods, for example:
+//            'DefaultFileMutex(...).Level(LevelError)'.
+//
+type settings struct {
+	// Минимальный уровень логов, которые попадут в накопитель (маршрут).
+	// The minimum level of logs that will get into the output (route).
+	level Level
+
+	// Ротация файлов, созданных инсталлером.
+	// Rotation of files created by the installer.
+	rotation *Rotation
+
+	// Права доступа для создаваемых файлов и директорий.
+	// Permissions for created files and directories.
+	perm, dirPerm os.FileMode
+
+	// Очередь файлов 'loggerFileMultithreading'.
+	// 'loggerFileMultithreading' file queue.
+	queue Queue
+
+	// Кодировщик, который используется вместо шаблона.
+	// Encoder that is used instead of the template.
+	encoder Encoder
+
+	// Файлы шаблонов, которые разбираются вместе с шаблоном инсталлера.
+	// Template files that are parsed together with the installer template.
+	templateFiles []string
+
+	// Потоки консоли и уровень, с которого логи выводятся в 'stderr'.
+	// Console streams and the level from which logs are output to 'stderr'.
+	stdout, stderr io.Writer
+	stderrLevel    *Level
+
+	// Формат и часовой пояс даты.
+	// Date format and time zone.
+	clock *clock
+}
+
+// newSettings : constructor
+//
+func newSettings() *settings {
+	s := new(settings)
+	s.level = LevelTrace
+	s.perm = 0666
+	s.dirPerm = 0755
+	s.queue = Queue{
+		Capacity: defaultQueueCapacity,
+		Overflow: OverflowBlock,
+	}
+	return s
+}
+
+// configure : запоминает изменение настроек и вызывает инсталлер.
+//             Изменения накапливаются в обратном порядке, чтобы
+//             последний вызванный метод ('.Level(a).Level(b)') имел приоритет.
+//
+//             remembers the settings change and calls the installer.
+//             Changes are accumulated in reverse order so that
+//             the last called method ('.Level(a).Level(b)') takes precedence.
+//
+func (logger *Logger) configure(apply func(s *settings), install func() error) error {
+	if logger.setup == nil {
+		defer func() {
+			logger.setup = nil
+		}()
+	}
+	logger.setup = append([]func(s *settings){apply}, logger.setup...)
+	return install()
+}
+
+// settings : настройки для инсталлера, который выполняется в данный момент.
+//            settings for the installer that is currently running.
+//
+func (logger *Logger) settings() *settings {
+	s := newSettings()
+	for _, apply := range logger.setup {
+		apply(s)
+	}
+	return s
+}
+
+// threshold : понижает минимальный уровень логгера, ниже которого
+//             логи отбрасываются ещё до получения информации о вызове и маршалинга.
+//
+//             lowers the minimum level of the logger, below which
+//             logs are discarded even before getting caller info and marshaling.
+//
+func (logger *Logger) threshold(lvl Level) {
+	if lvl < logger.level {
+		logger.level = lvl
+	}
+}
+
+// Level : устанавливает минимальный уровень для накопителей, созданных инсталлером.
+//         Для файлов уровень устанавливается каждому ключу файла.
+//
+//         sets the minimum level for the outputs created by the installer.
+//         For files, the level is set for each file key.
+//
+func (installer DefaultInstaller) Level(lvl Level) DefaultInstaller {
+	return func(logger *Logger) error {
+		return logger.configure(
+			func(s *settings) {
+				s.level = lvl
+			},
+			func() error {
+				return installer(logger)
+			},
+		)
+	}
+}
+
+// Level : устанавливает минимальный уровень для маршрута пакета.
+//         sets the minimum level for the package route.
+//
+func (installer PackageInstaller) Level(lvl Level) PackageInstaller {
+	return func(logger *Logger, pckg string) error {
+		return logger.configure(
+			func(s *settings) {
+				s.level = lvl
+			},
+			func() error {
+				return installer(logger, pckg)
+			},
+		)
+	}
+}
+
+// Rotate : включает ротацию для каждого ключа файла, созданного инсталлером.
+//          enables rotation for each file key created by the installer.
+//
+func (installer DefaultInstaller) Rotate(rotation Rotation) DefaultInstaller {
+	return func(logger *Logger) error {
+		return logger.configure(
+			func(s *settings) {
+				s.rotation = &rotation
+			},
+			func() error {
+				return installer(logger)
+			},
+		)
+	}
+}
+
+// Rotate : включает ротацию для файла, созданного инсталлером пакета.
+//          enables rotation for the file created by the package installer.
+//
+func (installer PackageInstaller) Rotate(rotation Rotation) PackageInstaller {
+	return func(logger *Logger, pckg string) error {
+		return logger.configure(
+			func(s *settings) {
+				s.rotation = &rotation
+			},
+			func() error {
+				return installer(logger, pckg)
+			},
+		)
+	}
+}
+
+// Permissions : права доступа для файлов и директорий, создаваемых инсталлером.
+//               permissions for files and directories created by the installer.
+//
+func (installer DefaultInstaller) Permissions(perm, dirPerm os.FileMode) DefaultInstaller {
+	return func(logger *Logger) error {
+		return logger.configure(
+			func(s *settings) {
+				s.perm = perm
+				s.dirPerm = dirPerm
+			},
+			func() error {
+				return installer(logger)
+			},
+		)
+	}
+}
+
+// Permissions : права доступа для файла и директорий, создаваемых инсталлером пакета.
+//               permissions for the file and directories created by the package installer.
+//
+func (installer PackageInstaller) Permissions(perm, dirPerm os.FileMode) PackageInstaller {
+	return func(logger *Logger, pckg string) error {
+		return logger.configure(
+			func(s *settings) {
+				s.perm = perm
+				s.dirPerm = dirPerm
+			},
+			func() error {
+				return installer(logger, pckg)
+			},
+		)
+	}
+}
+
+// Queue : размер и поведение при переполнении очереди каждого ключа файла 'loggerFileMultithreading'.
+//         size and overflow behavior of the queue of each 'loggerFileMultithreading' file key.
+//
+func (installer DefaultInstaller) Queue(queue Queue) DefaultInstaller {
+	return func(logger *Logger) error {
+		return logger.configure(
+			func(s *settings) {
+				s.queue = queue
+			},
+			func() error {
+				return installer(logger)
+			},
+		)
+	}
+}
+
+// Queue : размер и поведение при переполнении очереди файла 'loggerFileMultithreading' пакета.
+//         size and overflow behavior of the package 'loggerFileMultithreading' file queue.
+//
+func (installer PackageInstaller) Queue(queue Queue) PackageInstaller {
+	return func(logger *Logger, pckg string) error {
+		return logger.configure(
+			func(s *settings) {
+				s.queue = queue
+			},
+			func() error {
+				return installer(logger, pckg)
+			},
+		)
+	}
+}
+
+// Encoder : создаёт строки лога накопителей инсталлера через 'encoder' вместо шаблона.
+//           creates log lines of the installer outputs via 'encoder' instead of the template.
+//
+func (installer DefaultInstaller) Encoder(encoder Encoder) DefaultInstaller {
+	return func(logger *Logger) error {
+		return logger.configure(
+			func(s *settings) {
+				s.encoder = encoder
+			},
+			func() error {
+				return installer(logger)
+			},
+		)
+	}
+}
+
+// Encoder : создаёт строки лога накопителя инсталлера пакета через 'encoder' вместо шаблона.
+//           creates log lines of the package installer output via 'encoder' instead of the template.
+//
+func (installer PackageInstaller) Encoder(encoder Encoder) PackageInstaller {
+	return func(logger *Logger, pckg string) error {
+		return logger.configure(
+			func(s *settings) {
+				s.encoder = encoder
+			},
+			func() error {
+				return installer(logger, pckg)
+			},
+		)
+	}
+}
+
+// TemplateFiles : разбирает файлы шаблонов (шаблоны 'filepath.Glob') вместе с шаблоном инсталлера,
+//                 который может включать их через {{template "name.tmpl" .}}.
+//                 Если шаблон инсталлера пустой, используется первый файл.
+//
+//                 parses template files ('filepath.Glob' patterns) together with the installer template,
+//                 which can include them via {{template "name.tmpl" .}}.
+//                 If the installer template is empty, the first file is used.
+//
+func (installer DefaultInstaller) TemplateFiles(patterns ...string) DefaultInstaller {
+	return func(logger *Logger) error {
+		return logger.configure(
+			func(s *settings) {
+				s.templateFiles = patterns
+			},
+			func() error {
+				return installer(logger)
+			},
+		)
+	}
+}
+
+// TemplateFiles : разбирает файлы шаблонов вместе с шаблоном инсталлера пакета.
+//                 parses template files together with the package installer template.
+//
+func (installer PackageInstaller) TemplateFiles(patterns ...string) PackageInstaller {
+	return func(logger *Logger, pckg string) error {
+		return logger.configure(
+			func(s *settings) {
+				s.templateFiles = patterns
+			},
+			func() error {
+				return installer(logger, pckg)
+			},
+		)
+	}
+}
+
+// Stderr : логи уровня 'lvl' и выше выводятся консолью в 'stderr', остальные - в 'stdout'.
+//          По умолчанию - 'LevelError'.
+//
+//          logs of the level 'lvl' and above are output by the console to 'stderr', the rest - to 'stdout'.
+//          By default - 'LevelError'.
+//
+func (installer DefaultInstaller) Stderr(lvl Level) DefaultInstaller {
+	return func(logger *Logger) error {
+		return logger.configure(
+			func(s *settings) {
+				s.stderrLevel = &lvl
+			},
+			func() error {
+				return installer(logger)
+			},
+		)
+	}
+}
+
+// Stderr : уровень, с которого консоль пакета выводит логи в 'stderr'. Другие пакеты не затрагиваются.
+//          the level from which the package console outputs logs to 'stderr'. Other packages aren't affected.
+//
+func (installer PackageInstaller) Stderr(lvl Level) PackageInstaller {
+	return func(logger *Logger, pckg string) error {
+		return logger.configure(
+			func(s *settings) {
+				s.stderrLevel = &lvl
+			},
+			func() error {
+				return installer(logger, pckg)
+			},
+		)
+	}
+}
+
+// Writers : потоки консоли вместо 'os.Stdout' и 'os.Stderr'. Вместо потока 'nil' используется поток по умолчанию.
+//           Чтобы выводить все логи в один поток, передайте его дважды.
+//
+//           console streams instead of 'os.Stdout' and 'os.Stderr'. The default stream is used instead of a 'nil' stream.
+//           To output all logs to one stream, pass it twice.
+//
+func (installer DefaultInstaller) Writers(stdout, stderr io.Writer) DefaultInstaller {
+	return func(logger *Logger) error {
+		return logger.configure(
+			func(s *settings) {
+				s.stdout = stdout
+				s.stderr = stderr
+			},
+			func() error {
+				return installer(logger)
+			},
+		)
+	}
+}
+
+// Writers : потоки консоли пакета вместо 'os.Stdout' и 'os.Stderr'. Другие пакеты не затрагиваются.
+//           package console streams instead of 'os.Stdout' and 'os.Stderr'. Other packages aren't affected.
+//
+func (installer PackageInstaller) Writers(stdout, stderr io.Writer) PackageInstaller {
+	return func(logger *Logger, pckg string) error {
+		return logger.configure(
+			func(s *settings) {
+				s.stdout = stdout
+				s.stderr = stderr
+			},
+			func() error {
+				return installer(logger, pckg)
+			},
+		)
+	}
+}
+
+// TimeFormat : формат 'layout' и часовой пояс 'location' даты ('Entry.Date') накопителей инсталлера.
+//              'layout' - шаблон 'time.Format' (time.RFC3339Nano, "2006-01-02 15:04:05.000")
+//              или 'TimeUnix', 'TimeUnixMilli', 'TimeUnixNano'. Если 'location' равен nil, используется местное время.
+//              Кодировщики без '.TimeFormat(...)' выводят дату в RFC3339 с наносекундами в UTC.
+//
+//              format 'layout' and time zone 'location' of the date ('Entry.Date') of the installer outputs.
+//              'layout' is a 'time.Format' layout (time.RFC3339Nano, "2006-01-02 15:04:05.000")
+//              or 'TimeUnix', 'TimeUnixMilli', 'TimeUnixNano'. If 'location' is nil, local time is used.
+//              Encoders without '.TimeFormat(...)' output the date in RFC3339 with nanoseconds in UTC.
+//
+func (installer DefaultInstaller) TimeFormat(layout string, location *time.Location) DefaultInstaller {
+	return func(logger *Logger) error {
+		return logger.configure(
+			func(s *settings) {
+				s.clock = &clock{layout: layout, location: location}
+			},
+			func() error {
+				return installer(logger)
+			},
+		)
+	}
+}
+
+// TimeFormat : формат и часовой пояс даты накопителя инсталлера пакета.
+//              date format and time zone of the package installer output.
+//
+func (installer PackageInstaller) TimeFormat(layout string, location *time.Location) PackageInstaller {
+	return func(logger *Logger, pckg string) error {
+		return logger.configure(
+			func(s *settings) {
+				s.clock = &clock{layout: layout, location: location}
+			},
+			func() error {
+				return installer(logger, pckg)
+			},
+		)
+	}
+}
@@ -0,0 +1,108 @@
+package gologster
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const defaultStackDepth = 32
+
+// StackCapture : настройки захвата стека вызовов ('Logger.CaptureStack').
+//                call stack capture settings ('Logger.CaptureStack').
+//
+type StackCapture struct {
+	// Стек захватывается для логов уровня 'Level' и выше. По умолчанию - 'LevelError'.
+	// The stack is captured for logs of the 'Level' level and above. By default - 'LevelError'.
+	Level Level
+	// Максимальное количество кадров. По умолчанию - 32.
+	// Maximum number of frames. By default - 32.
+	Depth int
+	// Включать кадры пакета 'runtime'.
+	// Include frames of the 'runtime' package.
+	Runtime bool
+}
+
+// StackFrame : кадр стека вызовов. | call stack frame.
+//
+type StackFrame struct {
+	Func string `json:"func"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// Stack : стек вызовов, начиная с места вызова лога. В шаблонах '{{.Stack}}' выводится
+//         так же, как стек горутины при панике.
+//
+//         call stack starting from the place where the log is called. In templates '{{.Stack}}' is output
+//         the same way as the goroutine stack on panic.
+//
+type Stack []StackFrame
+
+// String : implement fmt.Stringer interface
+//
+func (stack Stack) String() string {
+	lines := make([]string, 0, len(stack))
+	for _, frame := range stack {
+		lines = append(lines, frame.Func+"\n\t"+frame.File+":"+strconv.Itoa(frame.Line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CaptureStack : включает захват стека вызовов ('Entry.Stack') для логов уровня 'capture.Level' и выше.
+//                Стек доступен в шаблонах как '{{.Stack}}', кодировщик 'JSONEncoder' выводит его массивом.
+//                'CaptureStack(nil)' отключает захват.
+//
+//                enables call stack capture ('Entry.Stack') for logs of the 'capture.Level' level and above.
+//                The stack is available in templates as '{{.Stack}}', the 'JSONEncoder' encoder outputs it as an array.
+//                'CaptureStack(nil)' disables capture.
+//
+// EXAMPLE: logger.CaptureStack(&gologster.StackCapture{Level: gologster.LevelError, Depth: 16})
+//
+func (logger *Logger) CaptureStack(capture *StackCapture) {
+	logger.mx.Lock()
+	defer logger.mx.Unlock()
+	logger.stack = normalizeStack(capture)
+}
+
+// normalizeStack : копия настроек со значениями по умолчанию. | copy of the settings with default values.
+//
+func normalizeStack(capture *StackCapture) *StackCapture {
+	if capture == nil {
+		return nil
+	}
+	normalized := *capture
+	if normalized.Level == 0 {
+		normalized.Level = LevelError
+	}
+	if normalized.Depth <= 0 {
+		normalized.Depth = defaultStackDepth
+	}
+	return &normalized
+}
+
+// captureStack : стек вызовов, начиная с кадра 'skip' (как в 'runtime.Callers').
+//                call stack starting from the frame 'skip' (as in 'runtime.Callers').
+//
+func captureStack(skip int, capture *StackCapture) Stack {
+	// Кадры 'runtime' пропускаются, поэтому запрашивается запас.
+	// 'runtime' frames are skipped, so a reserve is requested.
+	pcs := make([]uintptr, capture.Depth+8)
+	count := runtime.Callers(skip, pcs)
+	frames := runtime.CallersFrames(pcs[:count])
+	stack := make(Stack, 0, capture.Depth)
+	for len(stack) < capture.Depth {
+		frame, more := frames.Next()
+		if frame.Function != "" && (capture.Runtime || !strings.HasPrefix(frame.Function, "runtime.")) {
+			stack = append(stack, StackFrame{
+				Func: frame.Function,
+				File: frame.File,
+				Line: frame.Line,
+			})
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
+}
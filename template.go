@@ -0,0 +1,264 @@
+package gologster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// TemplateFuncs : стандартная библиотека функций шаблонов логов. Регистрируется всеми инсталлерами:
+//
+//                 pad N V          - дополняет V пробелами справа до N символов (при N < 0 - слева);
+//                 trunc N V        - обрезает V до N символов;
+//                 upper V, lower V - изменяет регистр V;
+//                 json V           - V в формате JSON, например: {{json .UserDataOriginal}};
+//                 time "layout" V  - время V ('.Time') в формате 'layout' ("RFC3339Nano", "unixmilli", ...);
+//                 color NAME V     - V в цвете NAME ("red", "green", ...) или в цвете уровня ("INFO", "ERROR", ...);
+//                 default D V      - D, если V пустое;
+//                 field "a.b" V    - поле или ключ карты значения V, например: {{field "user.id" .UserDataOriginal}}.
+//
+//                 standard function library of log templates. It's registered by all installers:
+//
+//                 pad N V          - pads V with spaces on the right up to N characters (if N < 0 - on the left);
+//                 trunc N V        - truncates V to N characters;
+//                 upper V, lower V - changes the case of V;
+//                 json V           - V in JSON format, for example: {{json .UserDataOriginal}};
+//                 time "layout" V  - time V ('.Time') in the 'layout' format ("RFC3339Nano", "unixmilli", ...);
+//                 color NAME V     - V in the color NAME ("red", "green", ...) or in the level color ("INFO", "ERROR", ...);
+//                 default D V      - D if V is empty;
+//                 field "a.b" V    - field or map key of the value V, for example: {{field "user.id" .UserDataOriginal}}.
+//
+// EXAMPLE: "{{.Level | pad 5 | color .Level}} {{.Time | time \"15:04:05\"}} {{.Func | trunc 20}} {{.Value}}"
+//
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"pad":     templatePad,
+		"trunc":   templateTrunc,
+		"upper":   templateUpper,
+		"lower":   templateLower,
+		"json":    templateJSON,
+		"time":    templateTime,
+		"color":   templateColor,
+		"default": templateDefault,
+		"field":   templateField,
+	}
+}
+
+const colorReset = "\x1b[0m"
+
+// templateColors : ANSI коды цветов 'color'. | ANSI color codes of 'color'.
+//
+var templateColors = map[string]string{
+	"black":   "\x1b[30m",
+	"red":     "\x1b[31m",
+	"green":   "\x1b[32m",
+	"yellow":  "\x1b[33m",
+	"blue":    "\x1b[34m",
+	"magenta": "\x1b[35m",
+	"cyan":    "\x1b[36m",
+	"white":   "\x1b[37m",
+	"gray":    "\x1b[90m",
+	"bold":    "\x1b[1m",
+	// Цвета уровней. | Level colors.
+	"TRACE": "\x1b[90m",
+	"DEBUG": "\x1b[34m",
+	"INFO":  "\x1b[32m",
+	"WARN":  "\x1b[33m",
+	"ERROR": "\x1b[31m",
+	"PANIC": "\x1b[35m",
+	"FATAL": "\x1b[1;31m",
+}
+
+func templatePad(width int, value interface{}) string {
+	str := fmt.Sprint(value)
+	left := width < 0
+	if left {
+		width = -width
+	}
+	count := utf8.RuneCountInString(str)
+	if count >= width {
+		return str
+	}
+	if left {
+		return strings.Repeat(" ", width-count) + str
+	}
+	return str + strings.Repeat(" ", width-count)
+}
+
+func templateTrunc(length int, value interface{}) string {
+	str := fmt.Sprint(value)
+	if length <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(str) <= length {
+		return str
+	}
+	return string([]rune(str)[:length])
+}
+
+func templateUpper(value interface{}) string {
+	return strings.ToUpper(fmt.Sprint(value))
+}
+
+func templateLower(value interface{}) string {
+	return strings.ToLower(fmt.Sprint(value))
+}
+
+func templateJSON(value interface{}) (string, error) {
+	out, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// templateTime : принимает только 'time.Time'. Строка '.Date' не разбирается, так как её формат
+//                зависит от '.TimeFormat(...)' выхода и неизвестен функции шаблона.
+//
+//                accepts only 'time.Time'. The '.Date' string isn't parsed, since its format
+//                depends on '.TimeFormat(...)' of the output and is unknown to the template function.
+//
+func templateTime(layout string, value interface{}) (string, error) {
+	switch date := value.(type) {
+	case time.Time:
+		return formatTime(date, layout), nil
+	case *time.Time:
+		if date == nil {
+			return "", nil
+		}
+		return formatTime(*date, layout), nil
+	case string:
+		return "", errors.New("time : string value isn't supported, use '.Time' instead of '.Date'")
+	}
+	return "", fmt.Errorf("time : unsupported value of type %T", value)
+}
+
+// templateColor : неизвестный цвет оставляет значение без изменений.
+//                 an unknown color leaves the value unchanged.
+//
+func templateColor(name string, value interface{}) string {
+	str := fmt.Sprint(value)
+	code, exist := templateColors[name]
+	if !exist {
+		code, exist = templateColors[strings.ToLower(name)]
+	}
+	if !exist {
+		return str
+	}
+	return code + str + colorReset
+}
+
+func templateDefault(alternative, value interface{}) interface{} {
+	if templateEmpty(value) {
+		return alternative
+	}
+	return value
+}
+
+func templateEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return reflect.DeepEqual(value, reflect.Zero(v.Type()).Interface())
+}
+
+// templateField : путь 'a.b' ищется по ключам карт, именам полей структур и их JSON тегам.
+//                 Если поле не найдено, возвращается nil, что позволяет использовать 'default'.
+//
+//                 the path 'a.b' is looked up by map keys, struct field names and their JSON tags.
+//                 If the field isn't found, nil is returned, which allows using 'default'.
+//
+func templateField(path string, value interface{}) interface{} {
+	current := reflect.ValueOf(value)
+	for _, name := range strings.Split(path, ".") {
+		for current.IsValid() && (current.Kind() == reflect.Ptr || current.Kind() == reflect.Interface) {
+			if current.IsNil() {
+				return nil
+			}
+			current = current.Elem()
+		}
+		switch current.Kind() {
+		case reflect.Map:
+			if current.Type().Key().Kind() != reflect.String {
+				return nil
+			}
+			current = current.MapIndex(reflect.ValueOf(name).Convert(current.Type().Key()))
+		case reflect.Struct:
+			current = templateStructField(current, name)
+		default:
+			return nil
+		}
+		if !current.IsValid() {
+			return nil
+		}
+	}
+	if !current.CanInterface() {
+		return nil
+	}
+	return current.Interface()
+}
+
+func templateStructField(value reflect.Value, name string) reflect.Value {
+	if field := value.FieldByName(name); field.IsValid() {
+		return field
+	}
+	for i := 0; i < value.NumField(); i++ {
+		tag := strings.Split(value.Type().Field(i).Tag.Get("json"), ",")[0]
+		if tag == name {
+			return value.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+// textTemplate : разбирает шаблон инсталлера со стандартной библиотекой функций.
+//                Если инсталлеру переданы файлы шаблонов ('.TemplateFiles(...)'), они разбираются
+//                в один набор с шаблоном, поэтому он может включать их через {{template "name.tmpl" .}}.
+//                Если шаблон пустой, используется первый файл.
+//
+//                parses the installer template with the standard function library.
+//                If template files are passed to the installer ('.TemplateFiles(...)'), they are parsed
+//                into one set with the template, so it can include them via {{template "name.tmpl" .}}.
+//                If the template is empty, the first file is used.
+//
+func (logger *Logger) textTemplate(name, str, alternative string) (*template.Template, error) {
+	patterns := logger.settings().templateFiles
+	if len(patterns) == 0 {
+		return getTextTemplate(name, str, alternative), nil
+	}
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, errors.New("template files aren't exist by pattern '" + pattern + "'")
+		}
+		files = append(files, matches...)
+	}
+	tmpl, err := template.New(name).Funcs(TemplateFuncs()).ParseFiles(files...)
+	if err != nil {
+		return nil, err
+	}
+	if str == "" {
+		return tmpl.Lookup(filepath.Base(files[0])), nil
+	}
+	if _, err := tmpl.Parse(str); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
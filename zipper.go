@@ -1,8 +1,335 @@
 package gologster
 
-import "time"
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
+// zipperLayout : формат времени в имени архива сегмента.
+//                time format in the segment archive name.
+//
+const zipperLayout = "2006-01-02T15-04-05.000"
+
+// Rotation : параметры ротации файла лога. Нулевое значение поля отключает соответствующее условие.
+//            log file rotation parameters. A zero field value disables the corresponding condition.
+//
+// Файл 'logs/file_1.txt' ротируется в архив 'logs/file_1-2006-01-02T15-04-05.000.txt.gz'.
+//
+// File 'logs/file_1.txt' is rotated to the archive 'logs/file_1-2006-01-02T15-04-05.000.txt.gz'.
+//
+type Rotation struct {
+	// Максимальный размер файла в байтах.
+	// Maximum file size in bytes.
+	MaxSize int64
+	// Максимальное время записи в один файл.
+	// Maximum time of writing to one file.
+	Interval time.Duration
+	// Максимальное количество хранимых архивов.
+	// Maximum number of stored archives.
+	MaxSegments int
+	// Максимальный суммарный размер хранимых архивов в байтах.
+	// Maximum total size of stored archives in bytes.
+	MaxTotalSize int64
+}
+
+// zipper : выполняет ротацию одного файла лога. | performs rotation of one log file.
+//
+// Запись в файл выполняется под 'lock.RLock()', а ротация под 'lock.Lock()',
+// поэтому ни одна строка не будет записана в файл после его переименования.
+// Сжатие переименованного файла выполняется уже без блокировки.
+//
+// Writing to the file is performed under 'lock.RLock()', and rotation under 'lock.Lock()',
+// so no line will be written to the file after it is renamed.
+// Compression of the renamed file is performed without the lock.
+//
 type zipper struct {
+	// Текущий размер файла и время начала записи в него в наносекундах (используются атомарно).
+	// Current file size and the start time of writing to it in nanoseconds (used atomically).
+	size, started int64
+
 	fileBasicLogger *loggerBaseFile
 	duration        time.Duration
+	rotation        Rotation
+	path            string
+	lock            sync.RWMutex
+	once            sync.Once
+}
+
+// newZipper : constructor
+//
+func newZipper(fileBasicLogger *loggerBaseFile, path string, rotation Rotation) *zipper {
+	z := new(zipper)
+	z.fileBasicLogger = fileBasicLogger
+	z.duration = rotation.Interval
+	z.rotation = rotation
+	z.path = path
+	return z
+}
+
+// rotate : регистрирует ротацию для файла 'path'. Вызывается под 'loggerBaseFile.mx'.
+//          registers rotation for the file 'path'. It's called under 'loggerBaseFile.mx'.
+//
+func (logger *loggerBaseFile) rotate(path string, rotation Rotation) {
+	logger.zippers[path] = newZipper(logger, path, rotation)
+}
+
+// write : выполняет 'output' для файла 'path', предварительно выполнив ротацию, если она необходима.
+//         performs 'output' for the file 'path', after performing rotation if necessary.
+//
+func (logger *loggerBaseFile) write(out *string, path string, output func(out *string, param ...string) error) error {
+	logger.mx.RLock()
+	z, exist := logger.zippers[path]
+	logger.mx.RUnlock()
+	if !exist {
+		return output(out, path)
+	}
+	if err := z.rotate(); err != nil {
+		logger.errorOutput(out, err)
+	}
+	z.lock.RLock()
+	defer z.lock.RUnlock()
+	err := output(out, path)
+	if err == nil {
+		atomic.AddInt64(&z.size, int64(len(*out))+1)
+	}
+	return err
+}
+
+// rotate : переименовывает файл и сжимает его, если превышен размер или время записи.
+//          renames the file and compresses it if the size or time of writing is exceeded.
+//
+func (z *zipper) rotate() error {
+	z.once.Do(func() {
+		atomic.StoreInt64(&z.started, time.Now().UnixNano())
+		if info, err := os.Stat(z.path); err == nil {
+			atomic.StoreInt64(&z.size, info.Size())
+		}
+	})
+	if !z.due() {
+		return nil
+	}
+	z.lock.Lock()
+	// Файл мог быть уже ротирован другой горутиной.
+	// The file could have already been rotated by another goroutine.
+	if !z.due() {
+		z.lock.Unlock()
+		return nil
+	}
+	segment, err := z.rename()
+	atomic.StoreInt64(&z.started, time.Now().UnixNano())
+	z.lock.Unlock()
+	if err != nil || segment == "" {
+		return err
+	}
+	if err := z.compress(segment); err != nil {
+		return err
+	}
+	return z.cleanup()
+}
+
+// due : проверяет, превышен ли размер или время записи в файл.
+//       checks whether the size or time of writing to the file is exceeded.
+//
+func (z *zipper) due() bool {
+	var (
+		bySize = z.rotation.MaxSize > 0 && atomic.LoadInt64(&z.size) >= z.rotation.MaxSize
+		byTime = z.duration > 0 && time.Since(time.Unix(0, atomic.LoadInt64(&z.started))) >= z.duration
+	)
+	return bySize || byTime
+}
+
+// rename : переименовывает текущий файл в сегмент и создаёт пустой файл на его месте.
+//          Вызывается под 'lock.Lock()'.
+//
+//          renames the current file to a segment and creates an empty file in its place.
+//          It's called under 'lock.Lock()'.
+//
+func (z *zipper) rename() (string, error) {
+	info, err := os.Stat(z.path)
+	if err != nil || info.Size() == 0 {
+		atomic.StoreInt64(&z.size, 0)
+		return "", nil
+	}
+	var (
+		prefix, ext = z.segmentName()
+		segment     = prefix + time.Now().Format(zipperLayout) + ext
+	)
+	for i := 1; z.exist(segment) || z.exist(segment+".gz"); i++ {
+		segment = prefix + time.Now().Format(zipperLayout) + "." + strconv.Itoa(i) + ext
+	}
+	if handle, err := z.fileBasicLogger.handle(z.path); err == nil {
+		// Следующая запись откроет новый файл.
+		// The next write will open the new file.
+		_ = handle.Close()
+	}
+	if err := os.Rename(z.path, segment); err != nil {
+		return "", errors.New("zipper.rename : " + err.Error())
+	}
+	atomic.StoreInt64(&z.size, 0)
+	file, err := os.OpenFile(z.path, os.O_CREATE|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return segment, errors.New("zipper.rename : " + err.Error())
+	}
+	return segment, file.Close()
+}
+
+// compress : сжимает сегмент в gzip архив и удаляет исходный сегмент.
+//            compresses the segment into a gzip archive and removes the original segment.
+//
+func (z *zipper) compress(segment string) error {
+	var (
+		fail = func(err error) error {
+			return errors.New("zipper.compress : " + err.Error())
+		}
+	)
+	src, err := os.Open(segment)
+	if err != nil {
+		return fail(err)
+	}
+	dst, err := os.OpenFile(segment+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	if err != nil {
+		_ = src.Close()
+		return fail(err)
+	}
+	archive := gzip.NewWriter(dst)
+	_, err = io.Copy(archive, src)
+	if err == nil {
+		err = archive.Close()
+	}
+	if errDst := dst.Close(); err == nil {
+		err = errDst
+	}
+	_ = src.Close()
+	if err != nil {
+		_ = os.Remove(segment + ".gz")
+		return fail(err)
+	}
+	if err := os.Remove(segment); err != nil {
+		return fail(err)
+	}
+	return nil
+}
+
+// cleanup : удаляет самые старые архивы, если превышено их количество или суммарный размер.
+//           removes the oldest archives if their number or total size is exceeded.
+//
+func (z *zipper) cleanup() error {
+	if z.rotation.MaxSegments <= 0 && z.rotation.MaxTotalSize <= 0 {
+		return nil
+	}
+	prefix, ext := z.segmentName()
+	infos, err := ioutil.ReadDir(filepath.Dir(z.path))
+	if err != nil {
+		return errors.New("zipper.cleanup : " + err.Error())
+	}
+	var segments []segment
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		if created, index, ok := z.parseSegment(info.Name(), filepath.Base(prefix), ext); ok {
+			segments = append(segments, segment{
+				path:    filepath.Join(filepath.Dir(z.path), info.Name()),
+				created: created,
+				index:   index,
+			})
+		}
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		if !segments[i].created.Equal(segments[j].created) {
+			return segments[i].created.Before(segments[j].created)
+		}
+		return segments[i].index < segments[j].index
+	})
+	var (
+		sizes = make([]int64, len(segments))
+		total int64
+	)
+	for i, segment := range segments {
+		if info, err := os.Stat(segment.path); err == nil {
+			sizes[i] = info.Size()
+			total += sizes[i]
+		}
+	}
+	for i := 0; i < len(segments); i++ {
+		var (
+			count    = len(segments) - i
+			overflow = z.rotation.MaxSegments > 0 && count > z.rotation.MaxSegments
+			oversize = z.rotation.MaxTotalSize > 0 && total > z.rotation.MaxTotalSize
+		)
+		if !overflow && !oversize {
+			break
+		}
+		if err := os.Remove(segments[i].path); err != nil {
+			return errors.New("zipper.cleanup : " + err.Error())
+		}
+		total -= sizes[i]
+	}
+	return nil
+}
+
+// segment : архив сегмента и время его создания из имени.
+//           segment archive and its creation time from the name.
+//
+type segment struct {
+	path    string
+	created time.Time
+	// Номер сегмента, созданного в ту же миллисекунду ('file_1-...000.1.txt.gz').
+	// Number of the segment created in the same millisecond ('file_1-...000.1.txt.gz').
+	index int
+}
+
+// parseSegment : разбирает имя архива 'prefix + время + [.N] + ext + .gz'. Архивы других файлов
+//                с тем же началом имени ('file_1-errors.txt') не подходят, так как время не разбирается.
+//
+//                parses the archive name 'prefix + time + [.N] + ext + .gz'. Archives of other files
+//                with the same name beginning ('file_1-errors.txt') don't match, since the time isn't parsed.
+//
+func (z *zipper) parseSegment(name, prefix, ext string) (time.Time, int, bool) {
+	suffix := ext + ".gz"
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) {
+		return time.Time{}, 0, false
+	}
+	middle := name[len(prefix) : len(name)-len(suffix)]
+	if len(middle) < len(zipperLayout) {
+		return time.Time{}, 0, false
+	}
+	created, err := time.ParseInLocation(zipperLayout, middle[:len(zipperLayout)], time.Local)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	index := 0
+	if rest := middle[len(zipperLayout):]; rest != "" {
+		if !strings.HasPrefix(rest, ".") {
+			return time.Time{}, 0, false
+		}
+		index, err = strconv.Atoi(rest[1:])
+		if err != nil || index <= 0 {
+			return time.Time{}, 0, false
+		}
+	}
+	return created, index, true
+}
+
+// segmentName : префикс и расширение имени сегмента: 'logs/file_1-' и '.txt'.
+//               prefix and extension of the segment name: 'logs/file_1-' and '.txt'.
+//
+func (z *zipper) segmentName() (string, string) {
+	ext := filepath.Ext(z.path)
+	return strings.TrimSuffix(z.path, ext) + "-", ext
+}
+
+func (z *zipper) exist(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
 }
@@ -0,0 +1,146 @@
+package gologster
+
+import (
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestZipperParseSegment(t *testing.T) {
+	var (
+		z       = newZipper(nil, "logs/file_1.txt", Rotation{})
+		created = time.Date(2021, 3, 4, 5, 6, 7, 8000000, time.Local)
+		stamp   = created.Format(zipperLayout)
+	)
+	tests := []struct {
+		name  string
+		index int
+		ok    bool
+	}{
+		{name: "file_1-" + stamp + ".txt.gz", ok: true},
+		{name: "file_1-" + stamp + ".2.txt.gz", index: 2, ok: true},
+		{name: "file_1-" + stamp + ".10.txt.gz", index: 10, ok: true},
+		{name: "file_1-" + stamp + ".txt"},
+		{name: "file_1-" + stamp + ".0.txt.gz"},
+		{name: "file_1-" + stamp + "x.txt.gz"},
+		{name: "file_1-errors-" + stamp + ".txt.gz"},
+		{name: "file_1-errors.txt.gz"},
+		{name: "file_10-" + stamp + ".txt.gz"},
+	}
+	for _, test := range tests {
+		got, index, ok := z.parseSegment(test.name, "file_1-", ".txt")
+		if ok != test.ok {
+			t.Errorf("%s : ok = %v, want %v", test.name, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if !got.Equal(created) || index != test.index {
+			t.Errorf("%s : got (%v, %d), want (%v, %d)", test.name, got, index, created, test.index)
+		}
+	}
+}
+
+func TestZipperCleanup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gologster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var (
+		at = func(minute int) string {
+			return time.Date(2021, 3, 4, 5, minute, 0, 0, time.Local).Format(zipperLayout)
+		}
+		own = []string{
+			"file_1-" + at(1) + ".txt.gz",
+			"file_1-" + at(2) + ".txt.gz",
+			"file_1-" + at(2) + ".2.txt.gz",
+			"file_1-" + at(2) + ".10.txt.gz",
+		}
+		foreign = []string{
+			"file_1-errors-" + at(0) + ".txt.gz",
+			"file_1-errors.txt.gz",
+			"file_1.txt",
+			"file_2-" + at(0) + ".txt.gz",
+		}
+	)
+	for _, name := range append(append([]string{}, own...), foreign...) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte("line\n"), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	z := newZipper(nil, filepath.Join(dir, "file_1.txt"), Rotation{MaxSegments: 2})
+	if err := z.cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	want := append([]string{own[2], own[3]}, foreign...)
+	sort.Strings(want)
+	if got := names(t, dir); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestZipperRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gologster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "file_1.txt")
+	logger := Default(
+		DefaultFileMutex(BaseLogTemplate, map[string]string{"file_1": path}).Rotate(Rotation{MaxSize: 256, MaxSegments: 3}),
+	)
+	for i := 0; i < 50; i++ {
+		logger.Info(i, OptionFileMutex("file_1"))
+	}
+	if err := logger.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var archives []string
+	for _, name := range names(t, dir) {
+		if strings.HasSuffix(name, ".gz") {
+			archives = append(archives, name)
+		}
+	}
+	if len(archives) != 3 {
+		t.Fatalf("got archives %v, want 3", archives)
+	}
+	for _, name := range archives {
+		file, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		archive, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		data, err := ioutil.ReadAll(archive)
+		_ = file.Close()
+		if err != nil || !strings.Contains(string(data), "level=[INFO]") {
+			t.Errorf("%s : unexpected archive content %q, %v", name, data, err)
+		}
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() >= 256+128 {
+		t.Errorf("current file isn't rotated : %v, %v", info, err)
+	}
+}
+
+func names(t *testing.T, dir string) []string {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	sort.Strings(names)
+	return names
+}